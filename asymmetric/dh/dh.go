@@ -0,0 +1,71 @@
+package dh
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/marelinaa/cipher-algorithms/asymmetric"
+)
+
+// Party is one side of the Diffie-Hellman key agreement
+type Party struct {
+	Private *big.Int // secret exponent a
+	Public  *big.Int // A = g^a mod p, sent to the other side
+}
+
+// NewParty creates a party with the given secret exponent, a random one is chosen when it is nil
+func NewParty(group *asymmetric.Group, private *big.Int) (*Party, error) {
+	if private == nil {
+		var err error
+		private, err = group.RandomExponent()
+		if err != nil {
+			return nil, err
+		}
+	}
+	if private.Sign() <= 0 || private.Cmp(group.Order()) >= 0 {
+		return nil, errors.New("secret exponent must be between 1 and p-2")
+	}
+
+	return &Party{
+		Private: private,
+		Public:  new(big.Int).Exp(group.G, private, group.P),
+	}, nil
+}
+
+// Shared computes the common key K = B^a mod p from the public value of the other side
+func (p *Party) Shared(group *asymmetric.Group, other *big.Int) (*big.Int, error) {
+	if other.Cmp(big.NewInt(1)) <= 0 || other.Cmp(group.P) >= 0 {
+		return nil, errors.New("public value must be between 2 and p-1")
+	}
+	return new(big.Int).Exp(other, p.Private, group.P), nil
+}
+
+// Exchange holds every value of one key agreement between Alice and Bob
+type Exchange struct {
+	Alice, Bob             *Party
+	AliceShared, BobShared *big.Int // K = B^a = A^b mod p, they are equal
+}
+
+// Run carries out the key agreement, nil secret exponents are chosen randomly
+func Run(group *asymmetric.Group, a, b *big.Int) (*Exchange, error) {
+	alice, err := NewParty(group, a)
+	if err != nil {
+		return nil, err
+	}
+	bob, err := NewParty(group, b)
+	if err != nil {
+		return nil, err
+	}
+
+	e := &Exchange{Alice: alice, Bob: bob}
+	e.AliceShared, err = alice.Shared(group, bob.Public)
+	if err != nil {
+		return nil, err
+	}
+	e.BobShared, err = bob.Shared(group, alice.Public)
+	if err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
@@ -0,0 +1,186 @@
+package elgamal
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/marelinaa/cipher-algorithms/asymmetric"
+)
+
+var one = big.NewInt(1)
+
+// PublicKey is the ElGamal public key y = g^x mod p
+type PublicKey struct {
+	asymmetric.Group
+	Y *big.Int
+}
+
+// PrivateKey holds the secret exponent x, it is nil when only the public key is known
+type PrivateKey struct {
+	PublicKey
+	X *big.Int
+}
+
+// GenerateKey generates a group with a prime of the given number of bits and a key in it
+func GenerateKey(ctx context.Context, bits int) (*PrivateKey, error) {
+	group, err := asymmetric.GenerateGroup(ctx, bits)
+	if err != nil {
+		return nil, err
+	}
+	return NewKey(group, nil)
+}
+
+// NewKey creates the key with the secret exponent x in the group, a random one is chosen when it is nil
+func NewKey(group *asymmetric.Group, x *big.Int) (*PrivateKey, error) {
+	if x == nil {
+		var err error
+		x, err = group.RandomExponent()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	key := &PrivateKey{
+		PublicKey: PublicKey{Group: *group, Y: new(big.Int).Exp(group.G, x, group.P)},
+		X:         x,
+	}
+	return key, key.Validate()
+}
+
+// Validate checks the group and, when x is known, that y = g^x mod p
+func (k *PrivateKey) Validate() error {
+	err := k.Group.Validate()
+	if err != nil {
+		return err
+	}
+	if k.Y == nil || k.Y.Sign() <= 0 || k.Y.Cmp(k.P) >= 0 {
+		return errors.New("elgamal public key must be between 1 and p-1")
+	}
+	if k.X == nil {
+		return nil
+	}
+	if k.X.Sign() <= 0 || k.X.Cmp(k.Order()) >= 0 {
+		return errors.New("elgamal secret exponent must be between 1 and p-2")
+	}
+	if new(big.Int).Exp(k.G, k.X, k.P).Cmp(k.Y) != 0 {
+		return errors.New("elgamal public key is not g^x mod p")
+	}
+	return nil
+}
+
+// Encryption holds the values of one ElGamal encryption
+type Encryption struct {
+	K *big.Int // session exponent
+	A *big.Int // a = g^k mod p
+	B *big.Int // b = y^k * m mod p
+}
+
+// Encrypt encrypts the number m < p with a random session exponent
+func Encrypt(pub *PublicKey, m *big.Int) (*Encryption, error) {
+	if m.Sign() <= 0 || m.Cmp(pub.P) >= 0 {
+		return nil, fmt.Errorf("message %v must be between 1 and p-1", m)
+	}
+
+	k, err := pub.RandomExponent()
+	if err != nil {
+		return nil, err
+	}
+
+	e := &Encryption{K: k, A: new(big.Int).Exp(pub.G, k, pub.P)}
+	e.B = new(big.Int).Exp(pub.Y, k, pub.P)
+	e.B.Mul(e.B, m).Mod(e.B, pub.P)
+	return e, nil
+}
+
+// Decryption holds the values of one ElGamal decryption
+type Decryption struct {
+	S        *big.Int // s = a^x mod p, equal to y^k
+	SInverse *big.Int // s^-1 mod p
+	M        *big.Int // m = b * s^-1 mod p
+}
+
+// Decrypt recovers m from the pair (a, b)
+func Decrypt(priv *PrivateKey, a, b *big.Int) (*Decryption, error) {
+	if priv.X == nil {
+		return nil, errors.New("decryption needs the secret exponent")
+	}
+	if a.Sign() <= 0 || a.Cmp(priv.P) >= 0 || b.Sign() <= 0 || b.Cmp(priv.P) >= 0 {
+		return nil, errors.New("ciphertext numbers must be between 1 and p-1")
+	}
+
+	d := &Decryption{S: new(big.Int).Exp(a, priv.X, priv.P)}
+	d.SInverse = new(big.Int).ModInverse(d.S, priv.P)
+	d.M = new(big.Int).Mul(b, d.SInverse)
+	d.M.Mod(d.M, priv.P)
+	return d, nil
+}
+
+// digest hashes the message with SHA-256 and reduces the hash modulo p-1
+func digest(message []byte, order *big.Int) *big.Int {
+	h := sha256.Sum256(message)
+	return new(big.Int).Mod(new(big.Int).SetBytes(h[:]), order)
+}
+
+// Signature holds the signature (r, s) and the values used to make it
+type Signature struct {
+	H        *big.Int // H(m) mod (p-1)
+	K        *big.Int // session exponent coprime with p-1
+	KInverse *big.Int // k^-1 mod (p-1)
+	R        *big.Int // r = g^k mod p
+	S        *big.Int // s = (H(m) - x*r) * k^-1 mod (p-1)
+}
+
+// Sign signs the message with H = SHA-256
+func Sign(priv *PrivateKey, message []byte) (*Signature, error) {
+	if priv.X == nil {
+		return nil, errors.New("signing needs the secret exponent")
+	}
+
+	order := priv.Order()
+	h := digest(message, order)
+	for {
+		k, err := priv.RandomCoprime()
+		if err != nil {
+			return nil, err
+		}
+
+		sig := &Signature{H: h, K: k, KInverse: new(big.Int).ModInverse(k, order)}
+		sig.R = new(big.Int).Exp(priv.G, k, priv.P)
+		sig.S = new(big.Int).Mul(priv.X, sig.R)
+		sig.S.Sub(h, sig.S)
+		sig.S.Mul(sig.S, sig.KInverse)
+		sig.S.Mod(sig.S, order)
+
+		// при s = 0 подпись не зависит от ключа, берется другое k
+		if sig.S.Sign() != 0 {
+			return sig, nil
+		}
+	}
+}
+
+// Verification holds both sides of the check y^r * r^s = g^H(m) mod p
+type Verification struct {
+	H     *big.Int
+	Left  *big.Int // y^r * r^s mod p
+	Right *big.Int // g^H(m) mod p
+}
+
+// Verify checks the signature (r, s), the returned values are filled even when it is not valid
+func Verify(pub *PublicKey, message []byte, r, s *big.Int) (*Verification, error) {
+	if r.Sign() <= 0 || r.Cmp(pub.P) >= 0 || s.Sign() <= 0 || s.Cmp(pub.Order()) >= 0 {
+		return nil, errors.New("signature is out of range")
+	}
+
+	v := &Verification{H: digest(message, pub.Order())}
+	v.Left = new(big.Int).Exp(pub.Y, r, pub.P)
+	v.Left.Mul(v.Left, new(big.Int).Exp(r, s, pub.P)).Mod(v.Left, pub.P)
+	v.Right = new(big.Int).Exp(pub.G, v.H, pub.P)
+
+	if v.Left.Cmp(v.Right) != 0 {
+		return v, errors.New("signature is not valid")
+	}
+	return v, nil
+}
@@ -0,0 +1,93 @@
+package asymmetric
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// Group is the multiplicative group modulo the prime P with the generator G
+type Group struct {
+	P *big.Int
+	G *big.Int
+}
+
+// GenerateGroup generates a safe prime p = 2q + 1 of the given number of bits and its smallest primitive root
+func GenerateGroup(ctx context.Context, bits int) (*Group, error) {
+	if bits < 4 {
+		return nil, fmt.Errorf("group prime must be at least 4 bits long")
+	}
+
+	for {
+		q, err := Prime(ctx, bits-1)
+		if err != nil {
+			return nil, err
+		}
+		p := new(big.Int).Lsh(q, 1)
+		p.Add(p, one)
+
+		prime, err := IsPrime(p)
+		if err != nil {
+			return nil, err
+		}
+		if !prime {
+			continue
+		}
+
+		// порядок элемента делит p-1 = 2q, поэтому g первообразный, если g^2 != 1 и g^q != 1
+		pMinusOne := new(big.Int).Sub(p, one)
+		for g := big.NewInt(2); g.Cmp(pMinusOne) < 0; g.Add(g, one) {
+			if new(big.Int).Exp(g, q, p).Cmp(one) != 0 {
+				return &Group{P: p, G: g}, nil
+			}
+		}
+	}
+}
+
+// Validate checks that P is prime and G lies in [2, P-2]
+func (g *Group) Validate() error {
+	if g.P == nil || g.G == nil {
+		return errors.New("group needs the prime and the generator")
+	}
+	if g.P.Cmp(big.NewInt(5)) < 0 {
+		return errors.New("group prime must be at least 5")
+	}
+
+	prime, err := IsPrime(g.P)
+	if err != nil {
+		return err
+	}
+	if !prime {
+		return fmt.Errorf("%v is not prime", g.P)
+	}
+
+	if g.G.Cmp(one) <= 0 || g.G.Cmp(new(big.Int).Sub(g.P, one)) >= 0 {
+		return errors.New("generator must be between 2 and p-2")
+	}
+	return nil
+}
+
+// Order returns p - 1, the order of the group
+func (g *Group) Order() *big.Int {
+	return new(big.Int).Sub(g.P, one)
+}
+
+// RandomExponent returns a random exponent in [2, p-2]
+func (g *Group) RandomExponent() (*big.Int, error) {
+	return RandomRange(two, new(big.Int).Sub(g.P, two))
+}
+
+// RandomCoprime returns a random exponent in [2, p-2] coprime with p - 1, so it has an inverse modulo p - 1
+func (g *Group) RandomCoprime() (*big.Int, error) {
+	order := g.Order()
+	for {
+		k, err := g.RandomExponent()
+		if err != nil {
+			return nil, err
+		}
+		if new(big.Int).GCD(nil, nil, k, order).Cmp(one) == 0 {
+			return k, nil
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package asymmetric
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"math/big"
+)
+
+// Rounds is the number of Miller-Rabin rounds, a composite passes all of them with probability below 4^-40
+const Rounds = 40
+
+var (
+	one   = big.NewInt(1)
+	two   = big.NewInt(2)
+	three = big.NewInt(3)
+)
+
+// smallPrimes are tried before Miller-Rabin to throw most candidates away quickly
+var smallPrimes = []int64{3, 5, 7, 11, 13, 17, 19, 23, 29, 31, 37, 41, 43, 47, 53, 59, 61, 67, 71, 73, 79, 83, 89, 97}
+
+// MillerRabin checks n for primality with the given number of rounds with random bases
+func MillerRabin(n *big.Int, rounds int) (bool, error) {
+	if n.Cmp(two) < 0 {
+		return false, nil
+	}
+	if n.Cmp(three) <= 0 {
+		return true, nil
+	}
+	if n.Bit(0) == 0 {
+		return false, nil
+	}
+
+	// n - 1 = 2^s * d, d нечетное
+	nMinusOne := new(big.Int).Sub(n, one)
+	d := new(big.Int).Set(nMinusOne)
+	s := 0
+	for d.Bit(0) == 0 {
+		d.Rsh(d, 1)
+		s++
+	}
+
+	// основание a выбирается из [2, n-2]
+	baseRange := new(big.Int).Sub(n, three)
+	for i := 0; i < rounds; i++ {
+		a, err := rand.Int(rand.Reader, baseRange)
+		if err != nil {
+			return false, err
+		}
+		a.Add(a, two)
+
+		x := new(big.Int).Exp(a, d, n)
+		if x.Cmp(one) == 0 || x.Cmp(nMinusOne) == 0 {
+			continue
+		}
+
+		composite := true
+		for r := 1; r < s; r++ {
+			x.Exp(x, two, n)
+			if x.Cmp(nMinusOne) == 0 {
+				composite = false
+				break
+			}
+		}
+		if composite {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// IsPrime checks n with trial division by small primes and Miller-Rabin
+func IsPrime(n *big.Int) (bool, error) {
+	for _, p := range smallPrimes {
+		prime := big.NewInt(p)
+		if n.Cmp(prime) == 0 {
+			return true, nil
+		}
+		if new(big.Int).Mod(n, prime).Sign() == 0 {
+			return false, nil
+		}
+	}
+	return MillerRabin(n, Rounds)
+}
+
+// Prime generates a random prime of exactly the given number of bits. The search stops
+// with the error of the context when it is canceled
+func Prime(ctx context.Context, bits int) (*big.Int, error) {
+	if bits < 3 {
+		return nil, fmt.Errorf("prime must be at least 3 bits long")
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		p, err := rand.Int(rand.Reader, new(big.Int).Lsh(one, uint(bits)))
+		if err != nil {
+			return nil, err
+		}
+		// старший бит задает длину, младший делает число нечетным
+		p.SetBit(p, bits-1, 1)
+		p.SetBit(p, 0, 1)
+
+		prime, err := IsPrime(p)
+		if err != nil {
+			return nil, err
+		}
+		if prime {
+			return p, nil
+		}
+	}
+}
+
+// RandomRange returns a random number in [min, max]
+func RandomRange(min, max *big.Int) (*big.Int, error) {
+	if min.Cmp(max) > 0 {
+		return nil, fmt.Errorf("empty range [%v, %v]", min, max)
+	}
+
+	n, err := rand.Int(rand.Reader, new(big.Int).Add(new(big.Int).Sub(max, min), one))
+	if err != nil {
+		return nil, err
+	}
+	return n.Add(n, min), nil
+}
@@ -0,0 +1,104 @@
+package rsa
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// mgf1 is the mask generation function of PKCS #1 with SHA-256
+func mgf1(seed []byte, length int) []byte {
+	mask := make([]byte, 0, length+sha256.Size)
+	counter := make([]byte, 4)
+	for i := uint32(0); len(mask) < length; i++ {
+		binary.BigEndian.PutUint32(counter, i)
+		h := sha256.Sum256(append(append([]byte{}, seed...), counter...))
+		mask = append(mask, h[:]...)
+	}
+	return mask[:length]
+}
+
+func xorBytes(dst, mask []byte) {
+	for i := range dst {
+		dst[i] ^= mask[i]
+	}
+}
+
+// MaxOAEPMessage returns the longest message in bytes that fits into one OAEP block of the key
+func MaxOAEPMessage(pub *PublicKey) int {
+	return (pub.N.BitLen()+7)/8 - 2*sha256.Size - 2
+}
+
+// EncryptOAEP encrypts the message with RSAES-OAEP of PKCS #1 v2.2 using SHA-256 and MGF1
+func EncryptOAEP(pub *PublicKey, message, label []byte) ([]byte, error) {
+	k := (pub.N.BitLen() + 7) / 8
+	if len(message) > MaxOAEPMessage(pub) {
+		return nil, fmt.Errorf("message of %d bytes is too long for oaep with a %d-bit key", len(message), pub.N.BitLen())
+	}
+
+	// EM = 0x00 || maskedSeed || maskedDB, DB = lHash || PS || 0x01 || M
+	em := make([]byte, k)
+	seed := em[1 : 1+sha256.Size]
+	db := em[1+sha256.Size:]
+
+	lHash := sha256.Sum256(label)
+	copy(db, lHash[:])
+	db[len(db)-len(message)-1] = 1
+	copy(db[len(db)-len(message):], message)
+
+	_, err := rand.Read(seed)
+	if err != nil {
+		return nil, err
+	}
+
+	xorBytes(db, mgf1(seed, len(db)))
+	xorBytes(seed, mgf1(db, len(seed)))
+
+	c, err := Encrypt(pub, new(big.Int).SetBytes(em))
+	if err != nil {
+		return nil, err
+	}
+	return c.FillBytes(make([]byte, k)), nil
+}
+
+// DecryptOAEP decrypts the ciphertext made by EncryptOAEP
+func DecryptOAEP(priv *PrivateKey, ciphertext, label []byte) ([]byte, error) {
+	k := (priv.N.BitLen() + 7) / 8
+	if len(ciphertext) != k || k < 2*sha256.Size+2 {
+		return nil, errors.New("oaep decryption error")
+	}
+
+	m, err := Decrypt(priv, new(big.Int).SetBytes(ciphertext))
+	if err != nil {
+		return nil, err
+	}
+	em := m.FillBytes(make([]byte, k))
+
+	seed := em[1 : 1+sha256.Size]
+	db := em[1+sha256.Size:]
+	xorBytes(seed, mgf1(db, len(seed)))
+	xorBytes(db, mgf1(seed, len(db)))
+
+	// ошибки формата не различаются, чтобы не давать оракул для атаки Мангера
+	lHash := sha256.Sum256(label)
+	valid := subtle.ConstantTimeByteEq(em[0], 0) & subtle.ConstantTimeCompare(db[:sha256.Size], lHash[:])
+
+	rest := db[sha256.Size:]
+	start := -1
+	for i, b := range rest {
+		if b == 1 && start < 0 {
+			start = i + 1
+		} else if b != 0 && start < 0 {
+			valid = 0
+		}
+	}
+	if valid != 1 || start < 0 {
+		return nil, errors.New("oaep decryption error")
+	}
+
+	return rest[start:], nil
+}
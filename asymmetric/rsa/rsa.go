@@ -0,0 +1,159 @@
+package rsa
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/marelinaa/cipher-algorithms/asymmetric"
+)
+
+// E is the public exponent used for generated keys with a modulus above it
+const E = 65537
+
+// MinBits is the smallest modulus accepted by GenerateKey
+const MinBits = 16
+
+var one = big.NewInt(1)
+
+// PublicKey is the RSA public key (n, e)
+type PublicKey struct {
+	N *big.Int
+	E *big.Int
+}
+
+// PrivateKey is the RSA private key. P, Q and Phi are kept for checking hand calculations,
+// they are nil when the key was read without them
+type PrivateKey struct {
+	PublicKey
+	D   *big.Int
+	P   *big.Int
+	Q   *big.Int
+	Phi *big.Int
+}
+
+// GenerateKey generates a key with a modulus of exactly the given number of bits
+func GenerateKey(ctx context.Context, bits int) (*PrivateKey, error) {
+	if bits < MinBits {
+		return nil, fmt.Errorf("rsa modulus must be at least %d bits long", MinBits)
+	}
+
+	for {
+		p, err := asymmetric.Prime(ctx, (bits+1)/2)
+		if err != nil {
+			return nil, err
+		}
+		q, err := asymmetric.Prime(ctx, bits/2)
+		if err != nil {
+			return nil, err
+		}
+
+		n := new(big.Int).Mul(p, q)
+		if p.Cmp(q) == 0 || n.BitLen() != bits {
+			continue
+		}
+
+		// phi(n) = (p-1)(q-1), d = e^-1 mod phi(n)
+		phi := new(big.Int).Mul(new(big.Int).Sub(p, one), new(big.Int).Sub(q, one))
+
+		// у маленьких учебных ключей берется наименьшая подходящая нечетная экспонента
+		e := big.NewInt(E)
+		if e.Cmp(n) >= 0 {
+			e.SetInt64(3)
+			for new(big.Int).GCD(nil, nil, e, phi).Cmp(one) != 0 {
+				e.Add(e, big.NewInt(2))
+			}
+		}
+		d := new(big.Int).ModInverse(e, phi)
+		if d == nil {
+			continue
+		}
+
+		return &PrivateKey{
+			PublicKey: PublicKey{N: n, E: e},
+			D:         d,
+			P:         p,
+			Q:         q,
+			Phi:       phi,
+		}, nil
+	}
+}
+
+// Validate checks that the key parts agree with each other as far as they are known
+func (k *PrivateKey) Validate() error {
+	err := k.PublicKey.Validate()
+	if err != nil {
+		return err
+	}
+	if k.D == nil {
+		return nil
+	}
+	if k.D.Sign() <= 0 || k.D.Cmp(k.N) >= 0 {
+		return errors.New("rsa private exponent must be between 1 and n-1")
+	}
+
+	// e*d = 1 mod lambda(n), поэтому m^(ed) = m для любого m; проверяется на одном числе
+	m := big.NewInt(2)
+	c := new(big.Int).Exp(m, k.E, k.N)
+	if new(big.Int).Exp(c, k.D, k.N).Cmp(m) != 0 {
+		return errors.New("rsa private exponent does not match the public key")
+	}
+	return nil
+}
+
+// Validate checks the public key
+func (k *PublicKey) Validate() error {
+	if k.N == nil || k.N.Cmp(big.NewInt(6)) < 0 {
+		return errors.New("rsa modulus is too small")
+	}
+	if k.E == nil || k.E.Cmp(one) <= 0 || k.E.Cmp(k.N) >= 0 {
+		return errors.New("rsa public exponent must be between 2 and n-1")
+	}
+	return nil
+}
+
+// Encrypt is textbook RSA: c = m^e mod n
+func Encrypt(pub *PublicKey, m *big.Int) (*big.Int, error) {
+	if m.Sign() < 0 || m.Cmp(pub.N) >= 0 {
+		return nil, fmt.Errorf("message %v must be between 0 and n-1", m)
+	}
+	return new(big.Int).Exp(m, pub.E, pub.N), nil
+}
+
+// Decrypt is textbook RSA: m = c^d mod n
+func Decrypt(priv *PrivateKey, c *big.Int) (*big.Int, error) {
+	if priv.D == nil {
+		return nil, errors.New("decryption needs the private exponent")
+	}
+	if c.Sign() < 0 || c.Cmp(priv.N) >= 0 {
+		return nil, fmt.Errorf("ciphertext %v must be between 0 and n-1", c)
+	}
+	return new(big.Int).Exp(c, priv.D, priv.N), nil
+}
+
+// digest hashes the message with SHA-256 and reduces the hash modulo n, so small classroom keys can sign too
+func digest(message []byte, n *big.Int) *big.Int {
+	h := sha256.Sum256(message)
+	return new(big.Int).Mod(new(big.Int).SetBytes(h[:]), n)
+}
+
+// Sign makes the textbook signature s = H(m)^d mod n with H = SHA-256
+func Sign(priv *PrivateKey, message []byte) (*big.Int, error) {
+	if priv.D == nil {
+		return nil, errors.New("signing needs the private exponent")
+	}
+	return new(big.Int).Exp(digest(message, priv.N), priv.D, priv.N), nil
+}
+
+// Verify checks that s^e mod n = H(m)
+func Verify(pub *PublicKey, message []byte, s *big.Int) error {
+	if s.Sign() < 0 || s.Cmp(pub.N) >= 0 {
+		return errors.New("signature must be between 0 and n-1")
+	}
+	if new(big.Int).Exp(s, pub.E, pub.N).Cmp(digest(message, pub.N)) != 0 {
+		return errors.New("signature is not valid")
+	}
+	return nil
+}
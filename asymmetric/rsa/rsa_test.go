@@ -0,0 +1,104 @@
+package rsa
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	stdrsa "crypto/rsa"
+	"crypto/sha256"
+	"math/big"
+	"testing"
+)
+
+// std converts the key to crypto/rsa
+func std(t *testing.T, k *PrivateKey) *stdrsa.PrivateKey {
+	t.Helper()
+	priv := &stdrsa.PrivateKey{
+		PublicKey: stdrsa.PublicKey{N: k.N, E: int(k.E.Int64())},
+		D:         k.D,
+		Primes:    []*big.Int{k.P, k.Q},
+	}
+	if err := priv.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	priv.Precompute()
+	return priv
+}
+
+func TestGenerateKey(t *testing.T) {
+	for _, bits := range []int{MinBits, 64, 1024} {
+		k, err := GenerateKey(context.Background(), bits)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if k.N.BitLen() != bits {
+			t.Errorf("modulus has %d bits, want %d", k.N.BitLen(), bits)
+		}
+		if err := k.Validate(); err != nil {
+			t.Errorf("%d-bit key: %v", bits, err)
+		}
+	}
+}
+
+// OAEP совместим с crypto/rsa в обе стороны
+func TestOAEPInterop(t *testing.T) {
+	k, err := GenerateKey(context.Background(), 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	priv := std(t, k)
+	message, label := []byte("ПРИВЕТ МИР"), []byte("label")
+
+	ciphertext, err := EncryptOAEP(&k.PublicKey, message, label)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := stdrsa.DecryptOAEP(sha256.New(), nil, priv, ciphertext, label)
+	if err != nil || !bytes.Equal(got, message) {
+		t.Errorf("crypto/rsa decrypted %q, %v, want %q", got, err, message)
+	}
+
+	ciphertext, err = stdrsa.EncryptOAEP(sha256.New(), rand.Reader, &priv.PublicKey, message, label)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err = DecryptOAEP(k, ciphertext, label)
+	if err != nil || !bytes.Equal(got, message) {
+		t.Errorf("DecryptOAEP = %q, %v, want %q", got, err, message)
+	}
+
+	if _, err := DecryptOAEP(k, ciphertext, []byte("other")); err == nil {
+		t.Error("DecryptOAEP accepted a wrong label")
+	}
+}
+
+func TestOAEPMessageLength(t *testing.T) {
+	k, err := GenerateKey(context.Background(), 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := EncryptOAEP(&k.PublicKey, make([]byte, MaxOAEPMessage(&k.PublicKey)), nil); err != nil {
+		t.Errorf("message of the maximal length: %v", err)
+	}
+	if _, err := EncryptOAEP(&k.PublicKey, make([]byte, MaxOAEPMessage(&k.PublicKey)+1), nil); err == nil {
+		t.Error("EncryptOAEP accepted a message longer than the maximum")
+	}
+}
+
+func TestSign(t *testing.T) {
+	k, err := GenerateKey(context.Background(), 512)
+	if err != nil {
+		t.Fatal(err)
+	}
+	message := []byte("message")
+	s, err := Sign(k, message)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Verify(&k.PublicKey, message, s); err != nil {
+		t.Error(err)
+	}
+	if err := Verify(&k.PublicKey, []byte("massage"), s); err == nil {
+		t.Error("Verify accepted the signature of another message")
+	}
+}
@@ -0,0 +1,36 @@
+package rsa
+
+import (
+	"math/big"
+
+	"github.com/marelinaa/cipher-algorithms/asymmetric"
+)
+
+// EncryptText splits the alphabet text into blocks below n and encrypts each block with textbook RSA
+func EncryptText(pub *PublicKey, text string, alphabetMap map[rune]int, power int) ([]*big.Int, error) {
+	blocks, err := asymmetric.EncodeText(text, alphabetMap, power, pub.N)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, m := range blocks {
+		blocks[i], err = Encrypt(pub, m)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return blocks, nil
+}
+
+// DecryptText decrypts the blocks made by EncryptText and decodes them into the alphabet text
+func DecryptText(priv *PrivateKey, blocks []*big.Int, alphabetMap map[rune]int, power int) (string, error) {
+	plain := make([]*big.Int, len(blocks))
+	for i, c := range blocks {
+		m, err := Decrypt(priv, c)
+		if err != nil {
+			return "", err
+		}
+		plain[i] = m
+	}
+	return asymmetric.DecodeText(plain, alphabetMap, power)
+}
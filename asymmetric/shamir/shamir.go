@@ -0,0 +1,90 @@
+package shamir
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/marelinaa/cipher-algorithms/asymmetric"
+)
+
+// Party holds the exponents of one side: c coprime with p-1 and d = c^-1 mod (p-1)
+type Party struct {
+	C *big.Int
+	D *big.Int
+}
+
+// Protocol is the Shamir three-pass (no-key) protocol between Alice and Bob over the prime P
+type Protocol struct {
+	P          *big.Int
+	Alice, Bob Party
+}
+
+// Generate generates a prime of the given number of bits and random exponents of both sides
+func Generate(ctx context.Context, bits int) (*Protocol, error) {
+	p, err := asymmetric.Prime(ctx, bits)
+	if err != nil {
+		return nil, err
+	}
+	return New(p, nil, nil)
+}
+
+// New creates the protocol with the exponents of Alice and Bob, random ones are chosen for nil exponents
+func New(p, alice, bob *big.Int) (*Protocol, error) {
+	// протокол не использует образующую, группа нужна для проверки p и выбора показателей
+	group := &asymmetric.Group{P: p, G: big.NewInt(2)}
+	err := group.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	pr := &Protocol{P: p}
+	for _, side := range []struct {
+		party *Party
+		c     *big.Int
+	}{{&pr.Alice, alice}, {&pr.Bob, bob}} {
+		c := side.c
+		if c == nil {
+			c, err = group.RandomCoprime()
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		side.party.C = c
+		side.party.D = new(big.Int).ModInverse(c, group.Order())
+		if c.Sign() <= 0 || side.party.D == nil {
+			return nil, fmt.Errorf("exponent %v must be positive and coprime with p-1", c)
+		}
+	}
+
+	return pr, nil
+}
+
+// Pass holds the three messages of the protocol, each of them is sent in the open
+type Pass struct {
+	X1 *big.Int // Alice to Bob: m^cA mod p
+	X2 *big.Int // Bob to Alice: x1^cB mod p
+	X3 *big.Int // Alice to Bob: x2^dA mod p
+}
+
+// Send carries out Alice's side and Bob's first step for the number m < p
+func (pr *Protocol) Send(m *big.Int) (*Pass, error) {
+	if m.Sign() <= 0 || m.Cmp(pr.P) >= 0 {
+		return nil, fmt.Errorf("message %v must be between 1 and p-1", m)
+	}
+
+	pass := &Pass{X1: new(big.Int).Exp(m, pr.Alice.C, pr.P)}
+	pass.X2 = new(big.Int).Exp(pass.X1, pr.Bob.C, pr.P)
+	pass.X3 = new(big.Int).Exp(pass.X2, pr.Alice.D, pr.P)
+	return pass, nil
+}
+
+// Receive is Bob's last step: m = x3^dB mod p
+func (pr *Protocol) Receive(x3 *big.Int) (*big.Int, error) {
+	if x3.Sign() <= 0 || x3.Cmp(pr.P) >= 0 {
+		return nil, errors.New("message must be between 1 and p-1")
+	}
+	return new(big.Int).Exp(x3, pr.Bob.D, pr.P), nil
+}
@@ -0,0 +1,97 @@
+package asymmetric
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// BlockLength returns how many symbols of an alphabet of the given power fit into one number below modulus.
+// Symbols are written as digits 1..power in base power+1, so a block never starts with a zero digit
+// and decoding recovers its length
+func BlockLength(power int, modulus *big.Int) int {
+	base := big.NewInt(int64(power + 1))
+	length := 0
+	for limit := new(big.Int).Set(base); limit.Cmp(modulus) <= 0; limit.Mul(limit, base) {
+		length++
+	}
+	return length
+}
+
+// EncodeText splits the alphabet text into blocks and turns each block into a number below modulus
+func EncodeText(text string, alphabetMap map[rune]int, power int, modulus *big.Int) ([]*big.Int, error) {
+	length := BlockLength(power, modulus)
+	if length == 0 {
+		return nil, fmt.Errorf("modulus %v is too small for an alphabet of power %d", modulus, power)
+	}
+
+	base := big.NewInt(int64(power + 1))
+	var blocks []*big.Int
+	runes := []rune(text)
+	for i := 0; i < len(runes); i += length {
+		block := new(big.Int)
+		for _, char := range runes[i:min(i+length, len(runes))] {
+			idx, ok := alphabetMap[char]
+			if !ok {
+				return nil, fmt.Errorf("symbol %q is not in the alphabet", char)
+			}
+			block.Mul(block, base)
+			block.Add(block, big.NewInt(int64(idx+1)))
+		}
+		blocks = append(blocks, block)
+	}
+
+	return blocks, nil
+}
+
+// DecodeText turns the numbers made by EncodeText back into the alphabet text
+func DecodeText(blocks []*big.Int, alphabetMap map[rune]int, power int) (string, error) {
+	reverseAlphabetMap := make(map[int]rune)
+	for char, idx := range alphabetMap {
+		reverseAlphabetMap[idx] = char
+	}
+
+	base := big.NewInt(int64(power + 1))
+	var text strings.Builder
+	for _, block := range blocks {
+		var symbols []rune
+		rest := new(big.Int).Set(block)
+		digit := new(big.Int)
+		for rest.Sign() > 0 {
+			rest.DivMod(rest, base, digit)
+			if digit.Sign() == 0 {
+				return "", fmt.Errorf("block %v is not an encoded text", block)
+			}
+			symbols = append(symbols, reverseAlphabetMap[int(digit.Int64())-1])
+		}
+
+		// цифры получены от младшей к старшей
+		for i := len(symbols) - 1; i >= 0; i-- {
+			text.WriteRune(symbols[i])
+		}
+	}
+
+	return text.String(), nil
+}
+
+// FormatNumbers writes the numbers in decimal separated by spaces
+func FormatNumbers(numbers []*big.Int) string {
+	parts := make([]string, len(numbers))
+	for i, n := range numbers {
+		parts[i] = n.String()
+	}
+	return strings.Join(parts, " ")
+}
+
+// ParseNumbers reads decimal numbers separated by spaces
+func ParseNumbers(input string) ([]*big.Int, error) {
+	var numbers []*big.Int
+	for _, field := range strings.Fields(input) {
+		n, ok := new(big.Int).SetString(field, 10)
+		if !ok || n.Sign() < 0 {
+			return nil, fmt.Errorf("%q is not a non-negative decimal number", field)
+		}
+		numbers = append(numbers, n)
+	}
+	return numbers, nil
+}
@@ -0,0 +1,278 @@
+// Package audit estimates the strength of classical keys: the size of the key space, the weak keys
+// that a valid key may still be, and the unicity distance, the length of ciphertext after which
+// only one key gives a meaningful plaintext
+package audit
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+	"unicode/utf8"
+
+	"github.com/marelinaa/cipher-algorithms/modmath"
+	"github.com/marelinaa/cipher-algorithms/verify"
+)
+
+// LanguageRate is the information of a natural language text in bits per letter (Shannon's estimate),
+// the rest of log2(power) bits per symbol is redundancy that lets a cryptanalyst tell the right key
+const LanguageRate = 1.5
+
+// Report is the result of an audit
+type Report struct {
+	Cipher   string
+	KeySpace *big.Int // number of different keys of the cipher with this alphabet (and keyword length)
+	Weak     []string // reasons why the key is weak, empty for a good key
+	Notes    []string // weaknesses of the cipher itself that no key avoids
+}
+
+// Bits returns log2 of the key space
+func (r Report) Bits() float64 {
+	return log2(r.KeySpace)
+}
+
+// UnicityDistance returns the number of ciphertext symbols after which the key is determined uniquely:
+// log2(key space) / (log2(power) - LanguageRate). It is +Inf when the alphabet is too small to carry redundancy
+func (r Report) UnicityDistance(power int) float64 {
+	redundancy := math.Log2(float64(power)) - LanguageRate
+	if redundancy <= 0 {
+		return math.Inf(1)
+	}
+	return r.Bits() / redundancy
+}
+
+// Auditor audits the key of a cipher written as in key.txt
+type Auditor func(key string, alphabetMap map[rune]int, power int) (Report, error)
+
+// Auditors are the ciphers whose keys can be audited, by cipher ID
+var Auditors = map[string]Auditor{
+	"caesar":       Caesar,
+	"affine":       Affine,
+	"substitution": Substitution,
+	"hill":         Hill,
+	"permutation":  Permutation,
+	"vigenere":     Vigenere,
+}
+
+// Caesar has power keys, the shift 0 leaves the text unchanged
+func Caesar(key string, alphabetMap map[rune]int, power int) (Report, error) {
+	k, err := verify.CaesarKey(key, alphabetMap)
+	if err != nil {
+		return Report{}, err
+	}
+
+	r := Report{Cipher: "caesar", KeySpace: big.NewInt(int64(power))}
+	if k == 0 {
+		r.Weak = append(r.Weak, "shift 0 leaves the text unchanged")
+	}
+	r.Notes = append(r.Notes, fmt.Sprintf("only %d keys, any of them is found by trying all shifts", power))
+	return r, nil
+}
+
+// Affine has phi(power) * power keys, K1 = 1 turns it into the Caesar cipher
+func Affine(key string, alphabetMap map[rune]int, power int) (Report, error) {
+	k, err := verify.AffineKey(key, alphabetMap, power)
+	if err != nil {
+		return Report{}, err
+	}
+
+	r := Report{Cipher: "affine", KeySpace: big.NewInt(int64(modmath.Totient(power)) * int64(power))}
+	switch {
+	case k.K1 == 1 && k.K2 == 0:
+		r.Weak = append(r.Weak, "K1 = 1 and K2 = 0 leave the text unchanged")
+	case k.K1 == 1:
+		r.Weak = append(r.Weak, "K1 = 1 makes it a Caesar cipher with the shift K2")
+	}
+	// K1^2 = 1 и K1*K2 + K2 = 0: зашифрование совпадает с расшифрованием
+	if modmath.Mod(k.K1*k.K1, power) == 1 && modmath.Mod(k.K1*k.K2+k.K2, power) == 0 && !(k.K1 == 1 && k.K2 == 0) {
+		r.Weak = append(r.Weak, "the key is involutory: encrypting twice gives the plaintext")
+	}
+	r.Notes = append(r.Notes, fmt.Sprintf("only %d keys, any of them is found by trying all pairs", r.KeySpace))
+	return r, nil
+}
+
+// Substitution has power! keys. Symbols the key leaves in place appear in the ciphertext unchanged
+func Substitution(key string, alphabetMap map[rune]int, power int) (Report, error) {
+	expanded, err := verify.ExpandSubstitutionKey(key, alphabetMap, power)
+	if err != nil {
+		return Report{}, err
+	}
+	perm, err := verify.SubstitutionKey(expanded, alphabetMap, power)
+	if err != nil {
+		return Report{}, err
+	}
+
+	r := Report{Cipher: "substitution", KeySpace: factorial(power)}
+	fixed, involutory := 0, true
+	for i, j := range perm {
+		if i == j {
+			fixed++
+		}
+		if perm[j] != i {
+			involutory = false
+		}
+	}
+	// у случайной подстановки в среднем одна неподвижная точка
+	switch {
+	case fixed == power:
+		r.Weak = append(r.Weak, "the key is the alphabet itself and leaves the text unchanged")
+	case fixed > max(1, power/10):
+		r.Weak = append(r.Weak, fmt.Sprintf("%d of %d symbols stay in place", fixed, power))
+	}
+	if involutory && fixed != power {
+		r.Weak = append(r.Weak, "the key is involutory (e.g. atbash): encrypting twice gives the plaintext")
+	}
+	if expanded != key {
+		r.Weak = append(r.Weak, "the key is a preset, it is guessed without searching the key space")
+	}
+	return r, nil
+}
+
+// Hill has as many keys as there are invertible 2x2 matrices modulo the power
+func Hill(key string, alphabetMap map[rune]int, power int) (Report, error) {
+	m, _, err := verify.HillModeKey(key, alphabetMap, power)
+	if err != nil {
+		return Report{}, err
+	}
+
+	r := Report{Cipher: "hill", KeySpace: invertibleMatrices(power)}
+	identity := [2][2]int{{1, 0}, {0, 1}}
+
+	// расстояние до единичной матрицы - число отличающихся элементов
+	distance := 0
+	for i := range m {
+		for j := range m[i] {
+			if m[i][j] != identity[i][j] {
+				distance++
+			}
+		}
+	}
+	switch {
+	case distance == 0:
+		r.Weak = append(r.Weak, "the identity matrix leaves the text unchanged")
+	case distance == 1:
+		r.Weak = append(r.Weak, "the matrix differs from the identity in one element, one symbol of every pair is not encrypted or is only shifted")
+	case m[0][1] == 0 && m[1][0] == 0:
+		r.Weak = append(r.Weak, "the matrix is diagonal, the symbols of a pair are encrypted independently by multiplication")
+	case m[0][1] == 0 || m[1][0] == 0:
+		r.Weak = append(r.Weak, "the matrix is triangular, one symbol of every pair is encrypted independently of the other")
+	}
+
+	var square [2][2]int
+	for i := range m {
+		for j := range m[i] {
+			square[i][j] = modmath.Mod(m[i][0]*m[0][j]+m[i][1]*m[1][j], power)
+		}
+	}
+	if square == identity && distance != 0 {
+		r.Weak = append(r.Weak, "the matrix is involutory: encrypting twice gives the plaintext")
+	}
+	return r, nil
+}
+
+// Permutation has length! keys for a keyword of that length
+func Permutation(key string, alphabetMap map[rune]int, power int) (Report, error) {
+	if key == "" {
+		return Report{}, fmt.Errorf("key can not be empty")
+	}
+	if err := verify.PermutationKey(key, alphabetMap, power); err != nil {
+		return Report{}, err
+	}
+	n := utf8.RuneCountInString(key)
+
+	r := Report{Cipher: "permutation", KeySpace: factorial(n)}
+	keyword := []rune(key)
+	sorted := sort.SliceIsSorted(keyword, func(i, j int) bool {
+		return alphabetMap[keyword[i]] < alphabetMap[keyword[j]]
+	})
+	switch {
+	case n < 2:
+		r.Weak = append(r.Weak, "a keyword of one symbol leaves the text unchanged")
+	case sorted:
+		r.Weak = append(r.Weak, "the keyword is in alphabetical order, the columns stay in place")
+	}
+	if n < 5 {
+		r.Weak = append(r.Weak, fmt.Sprintf("only %d orders of the columns", r.KeySpace))
+	}
+	return r, nil
+}
+
+// Vigenere has power^length keys. A periodic key is as strong as its shortest period
+func Vigenere(key string, alphabetMap map[rune]int, power int) (Report, error) {
+	if key == "" {
+		return Report{}, fmt.Errorf("key can not be empty")
+	}
+	if err := verify.VigenereKey(key, alphabetMap, power); err != nil {
+		return Report{}, err
+	}
+	keyword := []rune(key)
+	n := len(keyword)
+	period := period(keyword)
+
+	r := Report{Cipher: "vigenere", KeySpace: new(big.Int).Exp(big.NewInt(int64(power)), big.NewInt(int64(period)), nil)}
+	if n < 3 {
+		r.Weak = append(r.Weak, fmt.Sprintf("a key of %d symbols is found by frequency analysis of the %d columns of the text", n, n))
+	}
+	switch {
+	case period == 1 && n > 1:
+		r.Weak = append(r.Weak, "all symbols of the key are equal, it is a Caesar cipher")
+	case period < n:
+		r.Weak = append(r.Weak, fmt.Sprintf("the key repeats with period %d, it is as strong as %q", period, string(keyword[:period])))
+	}
+	for _, char := range keyword {
+		if alphabetMap[char] == 0 {
+			r.Weak = append(r.Weak, fmt.Sprintf("symbol %q of the key is a zero shift, the text at its positions is not encrypted", char))
+			break
+		}
+	}
+	return r, nil
+}
+
+// period returns the length of the shortest prefix the key is a repetition of
+func period(key []rune) int {
+	n := len(key)
+	for p := 1; p < n; p++ {
+		if n%p != 0 {
+			continue
+		}
+		repeats := true
+		for i := p; i < n; i++ {
+			if key[i] != key[i-p] {
+				repeats = false
+				break
+			}
+		}
+		if repeats {
+			return p
+		}
+	}
+	return n
+}
+
+// invertibleMatrices returns |GL(2, Z_n)|: the product over p^k of n of p^(4(k-1)) * (p^2-1) * (p^2-p)
+func invertibleMatrices(n int) *big.Int {
+	count := big.NewInt(1)
+	primes, exponents := modmath.Factor(n)
+	for i, p := range primes {
+		bp := big.NewInt(int64(p))
+		count.Mul(count, new(big.Int).Exp(bp, big.NewInt(int64(4*(exponents[i]-1))), nil))
+		count.Mul(count, big.NewInt(int64(p*p-1)))
+		count.Mul(count, big.NewInt(int64(p*p-p)))
+	}
+	return count
+}
+
+func factorial(n int) *big.Int {
+	return new(big.Int).MulRange(1, int64(max(n, 1)))
+}
+
+// log2 returns log2(x) for numbers too large for float64
+func log2(x *big.Int) float64 {
+	if x.Sign() <= 0 {
+		return math.Inf(-1)
+	}
+	mant := new(big.Float)
+	exp := new(big.Float).SetInt(x).MantExp(mant)
+	m, _ := mant.Float64()
+	return float64(exp) + math.Log2(m)
+}
@@ -0,0 +1,133 @@
+package audit
+
+import (
+	"math"
+	"math/big"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/marelinaa/cipher-algorithms/verify"
+)
+
+const alphabet = "АБВГДЕЁЖЗИЙКЛМНОПРСТУФХЦЧШЩЪЫЬЭЮЯ "
+
+func TestWeakKeys(t *testing.T) {
+	alphabetMap, err := verify.Alphabet(alphabet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	power := utf8.RuneCountInString(alphabet)
+
+	// weak перечисляет начала ожидаемых причин, пустой список - сильный ключ
+	tests := []struct {
+		cipher, key string
+		weak        []string
+	}{
+		{"caesar", "А", []string{"shift 0"}},
+		{"caesar", "Г", nil},
+
+		{"affine", "БА", []string{"K1 = 1 and K2 = 0"}},
+		{"affine", "БГ", []string{"K1 = 1 makes it a Caesar cipher"}},
+		{"affine", " Г", []string{"the key is involutory"}}, // 33*33 = 1 mod 34
+		{"affine", "ГД", nil},
+
+		{"substitution", alphabet, []string{"the key is the alphabet itself"}},
+		{"substitution", "БА" + alphabet[4:], []string{"32 of 34 symbols", "the key is involutory"}},
+		{"substitution", "atbash", []string{"the key is involutory", "the key is a preset"}},
+		{"substitution", "rot|1", []string{"the key is a preset"}},
+
+		{"hill", "БААБ", []string{"the identity matrix"}},
+		{"hill", "БГАБ", []string{"the matrix differs from the identity in one element"}},
+		{"hill", "ГААГ", []string{"the matrix is diagonal"}},
+		{"hill", "ГДАБ", []string{"the matrix is triangular"}},
+		{"hill", "АББА", []string{"the matrix is involutory"}},
+		{"hill", "ГДЕИ", nil},
+
+		{"permutation", "А", []string{"a keyword of one symbol", "only 1 orders"}},
+		{"permutation", "АБВ", []string{"the keyword is in alphabetical order", "only 6 orders"}},
+		{"permutation", "ВАГБЕД", nil},
+
+		{"vigenere", "ККК", []string{"all symbols of the key are equal"}},
+		{"vigenere", "КЛКЛ", []string{"the key repeats with period 2"}},
+		{"vigenere", "КА", []string{"a key of 2 symbols", `symbol 'А' of the key is a zero shift`}},
+		{"vigenere", "КЛЮЧ", nil},
+	}
+	for _, tt := range tests {
+		r, err := Auditors[tt.cipher](tt.key, alphabetMap, power)
+		if err != nil {
+			t.Errorf("%s %q: %v", tt.cipher, tt.key, err)
+			continue
+		}
+		if len(r.Weak) != len(tt.weak) {
+			t.Errorf("%s %q: weak = %q, want %d reasons starting with %q", tt.cipher, tt.key, r.Weak, len(tt.weak), tt.weak)
+			continue
+		}
+		for i, reason := range r.Weak {
+			if !strings.HasPrefix(reason, tt.weak[i]) {
+				t.Errorf("%s %q: weak[%d] = %q, want it to start with %q", tt.cipher, tt.key, i, reason, tt.weak[i])
+			}
+		}
+	}
+}
+
+func TestInvertibleMatrices(t *testing.T) {
+	// перебор всех матриц 2x2 по модулю n
+	for n := 2; n <= 12; n++ {
+		want := int64(0)
+		for a := 0; a < n; a++ {
+			for b := 0; b < n; b++ {
+				for c := 0; c < n; c++ {
+					for d := 0; d < n; d++ {
+						det := ((a*d-b*c)%n + n) % n
+						if gcd(det, n) == 1 {
+							want++
+						}
+					}
+				}
+			}
+		}
+		if got := invertibleMatrices(n); got.Int64() != want {
+			t.Errorf("invertibleMatrices(%d) = %s, want %d", n, got, want)
+		}
+	}
+
+	// |GL(2, Z_26)|, число ключей классического шифра Хилла для латиницы
+	if got := invertibleMatrices(26); got.Int64() != 157248 {
+		t.Errorf("invertibleMatrices(26) = %s, want 157248", got)
+	}
+}
+
+func gcd(a, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+func TestUnicityDistance(t *testing.T) {
+	tests := []struct {
+		keySpace *big.Int
+		power    int
+		want     float64
+	}{
+		// 35 бит ключа, избыточность log2(32) - 1.5 = 3.5 бита на символ
+		{new(big.Int).Lsh(big.NewInt(1), 35), 32, 10},
+		{big.NewInt(34), 34, math.Log2(34) / (math.Log2(34) - LanguageRate)},
+		// 34! не помещается в int64, log2(34!) = 127.795
+		{factorial(34), 34, 127.795 / (math.Log2(34) - LanguageRate)},
+		{big.NewInt(1024), 2, math.Inf(1)},
+	}
+	for _, tt := range tests {
+		got := Report{KeySpace: tt.keySpace}.UnicityDistance(tt.power)
+		if math.IsInf(tt.want, 1) {
+			if !math.IsInf(got, 1) {
+				t.Errorf("UnicityDistance(%s, %d) = %v, want +Inf", tt.keySpace, tt.power, got)
+			}
+			continue
+		}
+		if math.Abs(got-tt.want) > 0.01 {
+			t.Errorf("UnicityDistance(%s, %d) = %v, want %v", tt.keySpace, tt.power, got, tt.want)
+		}
+	}
+}
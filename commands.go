@@ -0,0 +1,1023 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"flag"
+	"fmt"
+	"hash"
+	"io"
+	"log"
+	"math"
+	"math/big"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+	"unicode/utf8"
+
+	"github.com/marelinaa/cipher-algorithms/asymmetric"
+	"github.com/marelinaa/cipher-algorithms/asymmetric/dh"
+	"github.com/marelinaa/cipher-algorithms/audit"
+	"github.com/marelinaa/cipher-algorithms/container"
+	"github.com/marelinaa/cipher-algorithms/ecc"
+	"github.com/marelinaa/cipher-algorithms/gost"
+	"github.com/marelinaa/cipher-algorithms/kdf"
+	"github.com/marelinaa/cipher-algorithms/keygen"
+	"github.com/marelinaa/cipher-algorithms/keyring"
+	"github.com/marelinaa/cipher-algorithms/keys"
+	"github.com/marelinaa/cipher-algorithms/registry"
+	"github.com/marelinaa/cipher-algorithms/server"
+	"github.com/marelinaa/cipher-algorithms/stream"
+	"github.com/marelinaa/cipher-algorithms/trace"
+	"github.com/marelinaa/cipher-algorithms/tui"
+	"github.com/marelinaa/cipher-algorithms/verify"
+)
+
+// commands run instead of the interactive menu when the program is started with arguments
+var commands = map[string]func(args []string) error{
+	"audit":     auditCommand,
+	"decrypt":   decryptCommand,
+	"derive":    deriveCommand,
+	"dh":        dhCommand,
+	"ecc":       eccCommand,
+	"encrypt":   encryptCommand,
+	"hash":      hashCommand,
+	"keys":      keysCommand,
+	"keystream": keystreamCommand,
+	"serve":     serveCommand,
+	"sign":      signCommand,
+	"verify":    verifyCommand,
+}
+
+// keystreamCommand prints the first outputs of a stream cipher generator and its period
+func keystreamCommand(args []string) error {
+	flags := flag.NewFlagSet("keystream", flag.ContinueOnError)
+	n := flags.Int("n", 64, "number of outputs to print")
+	limit := flags.Int("limit", 1<<20, "number of outputs to try when looking for the period")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: keystream [-n outputs] [-limit outputs] cipher key")
+		flags.PrintDefaults()
+	}
+
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return fmt.Errorf("keystream needs the cipher and the key")
+	}
+
+	c, ok := registry.Lookup(flags.Arg(0))
+	if !ok || c.Keystream == nil {
+		var ids []string
+		for _, c := range registry.All() {
+			if c.Keystream != nil {
+				ids = append(ids, c.ID)
+			}
+		}
+		return fmt.Errorf("%q is not a stream cipher, choose one of %s", flags.Arg(0), strings.Join(ids, ", "))
+	}
+
+	g, err := c.Keystream(flags.Arg(1))
+	if err != nil {
+		return err
+	}
+
+	// биты выводятся группами по 8, байты - в шестнадцатеричном виде
+	var outputs strings.Builder
+	for i := 0; i < *n; i++ {
+		if i > 0 && (g.Bits() == 8 || i%8 == 0) {
+			outputs.WriteByte(' ')
+		}
+		if g.Bits() == 8 {
+			fmt.Fprintf(&outputs, "%02x", g.Next())
+		} else {
+			fmt.Fprintf(&outputs, "%d", g.Next())
+		}
+	}
+	fmt.Printf("first %d outputs: %s\n", *n, outputs.String())
+
+	// период ищется заново от начального состояния
+	g, err = c.Keystream(flags.Arg(1))
+	if err != nil {
+		return err
+	}
+	period, found := stream.Period(g, *limit)
+	if found {
+		fmt.Printf("period: %d\n", period)
+	} else {
+		fmt.Printf("period: more than %d outputs\n", *limit)
+	}
+
+	return nil
+}
+
+// signingCipher looks up the cipher that can sign files
+func signingCipher(id string) (registry.Cipher, error) {
+	c, ok := registry.Lookup(id)
+	if ok && c.Sign != nil {
+		return c, nil
+	}
+
+	var ids []string
+	for _, c := range registry.All() {
+		if c.Sign != nil {
+			ids = append(ids, c.ID)
+		}
+	}
+	return registry.Cipher{}, fmt.Errorf("%q can not sign, choose one of %s", id, strings.Join(ids, ", "))
+}
+
+// signCommand prints the signature of a file made with the private key from the key file
+func signCommand(args []string) error {
+	flags := flag.NewFlagSet("sign", flag.ContinueOnError)
+	keyPath := flags.String("key", keyFile, "file with the private key")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: sign [-key file] cipher file")
+		flags.PrintDefaults()
+	}
+
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return fmt.Errorf("sign needs the cipher and the file")
+	}
+
+	c, err := signingCipher(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	key, err := openAndExtractText(*keyPath)
+	if err != nil {
+		return err
+	}
+	message, err := os.ReadFile(flags.Arg(1))
+	if err != nil {
+		return err
+	}
+
+	// ключ вида new|... генерируется и сохраняется, как в меню
+	if c.NewKey != nil {
+		generatedKey, generated, err := c.NewKey(context.Background(), key, nil, 0)
+		if err != nil {
+			return err
+		}
+		if generated {
+			key = generatedKey
+			WriteToFile(*keyPath, key)
+			fmt.Printf("generated key saved to %s\n", *keyPath)
+		}
+	}
+
+	signature, err := c.Sign(message, key)
+	if err != nil {
+		return err
+	}
+	fmt.Println(signature)
+	return nil
+}
+
+// verifyCommand checks the signature of a file with the public key from the key file
+func verifyCommand(args []string) error {
+	flags := flag.NewFlagSet("verify", flag.ContinueOnError)
+	keyPath := flags.String("key", keyFile, "file with the public key")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: verify [-key file] cipher file signature")
+		flags.PrintDefaults()
+	}
+
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+	if flags.NArg() != 3 {
+		flags.Usage()
+		return fmt.Errorf("verify needs the cipher, the file and the signature")
+	}
+
+	c, err := signingCipher(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	key, err := openAndExtractText(*keyPath)
+	if err != nil {
+		return err
+	}
+	message, err := os.ReadFile(flags.Arg(1))
+	if err != nil {
+		return err
+	}
+
+	err = c.Verify(message, key, flags.Arg(2))
+	if err != nil {
+		return err
+	}
+	fmt.Println("signature is valid")
+	return nil
+}
+
+// dhCommand carries out the Diffie-Hellman key agreement and prints every value of it
+func dhCommand(args []string) error {
+	flags := flag.NewFlagSet("dh", flag.ContinueOnError)
+	bits := flags.Int("bits", 0, "generate a group with a safe prime of this many bits")
+	groupString := flags.String("group", "", "group written as p|g, read from the key file by default")
+	a := flags.String("a", "", "secret exponent of Alice, random by default")
+	b := flags.String("b", "", "secret exponent of Bob, random by default")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: dh [-bits n | -group p|g] [-a secret] [-b secret]")
+		flags.PrintDefaults()
+	}
+
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	var group *asymmetric.Group
+	if *bits > 0 {
+		group, err = asymmetric.GenerateGroup(context.Background(), *bits)
+	} else {
+		if *groupString == "" {
+			*groupString, err = openAndExtractText(keyFile)
+			if err != nil {
+				return err
+			}
+		}
+		group, err = verify.GroupKey(*groupString)
+	}
+	if err != nil {
+		return err
+	}
+
+	secrets := make([]*big.Int, 2)
+	for i, secret := range []string{*a, *b} {
+		if secret == "" {
+			continue
+		}
+		n, ok := new(big.Int).SetString(secret, 10)
+		if !ok {
+			return fmt.Errorf("secret exponent must be a decimal number: %q", secret)
+		}
+		secrets[i] = n
+	}
+
+	e, err := dh.Run(group, secrets[0], secrets[1])
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("p = %v\ng = %v\n", group.P, group.G)
+	fmt.Printf("Alice: a = %v, A = g^a = %v\n", e.Alice.Private, e.Alice.Public)
+	fmt.Printf("Bob:   b = %v, B = g^b = %v\n", e.Bob.Private, e.Bob.Public)
+	fmt.Printf("Alice: K = B^a = %v\n", e.AliceShared)
+	fmt.Printf("Bob:   K = A^b = %v\n", e.BobShared)
+	return nil
+}
+
+// hashes are the hash functions of the hash command
+var hashes = map[string]func() hash.Hash{
+	"sha256":      sha256.New,
+	"streebog256": gost.NewStreebog256,
+	"streebog512": gost.NewStreebog512,
+}
+
+// fileDigest hashes the file and returns the digest in base64
+func fileDigest(path string, newHash func() hash.Hash) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	h := newHash()
+	_, err = io.Copy(h, file)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(h.Sum(nil)), nil
+}
+
+// hashCommand prints the base64 digests of files like the Hash_Calc tool from README.txt,
+// or compares the digest of a file with the expected one
+func hashCommand(args []string) error {
+	flags := flag.NewFlagSet("hash", flag.ContinueOnError)
+	algorithm := flags.String("alg", "streebog256", "hash function: sha256, streebog256 or streebog512")
+	expected := flags.String("verify", "", "expected base64 digest, the file name form without / is accepted too")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: hash [-alg name] [-verify digest] file...")
+		flags.PrintDefaults()
+	}
+
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return fmt.Errorf("hash needs at least one file")
+	}
+
+	newHash, ok := hashes[*algorithm]
+	if !ok {
+		return fmt.Errorf("unknown hash function %q, choose one of sha256, streebog256, streebog512", *algorithm)
+	}
+
+	failed := 0
+	for _, path := range flags.Args() {
+		digest, err := fileDigest(path, newHash)
+		if err != nil {
+			return err
+		}
+
+		if *expected == "" {
+			fmt.Printf("%s  %s\n", digest, path)
+			continue
+		}
+
+		// в именах файлов из README.txt символ / выброшен
+		if *expected == digest || *expected == strings.ReplaceAll(digest, "/", "") {
+			fmt.Printf("%s: OK\n", path)
+		} else {
+			fmt.Printf("%s: FAILED, digest is %s\n", path, digest)
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d files do not match the expected digest", failed, flags.NArg())
+	}
+	return nil
+}
+
+// eccCommand does point arithmetic on a curve so hand calculations can be checked
+func eccCommand(args []string) error {
+	flags := flag.NewFlagSet("ecc", flag.ContinueOnError)
+	curveName := flags.String("curve", "e17", "curve name")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: ecc [-curve name] curves | points | add P Q | double P | mul k P")
+		fmt.Fprintln(flags.Output(), "points are written as x,y; O is the point at infinity and G the base point")
+		flags.PrintDefaults()
+	}
+
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return fmt.Errorf("ecc needs an operation")
+	}
+
+	if flags.Arg(0) == "curves" {
+		for _, c := range ecc.Curves {
+			fmt.Printf("%s: y^2 = x^3 + %vx + %v mod %v, G = (%v, %v), q = %v\n", c.Name, c.A, c.B, c.P, c.X, c.Y, c.Q)
+		}
+		return nil
+	}
+
+	c, err := ecc.Lookup(*curveName)
+	if err != nil {
+		return err
+	}
+
+	// points разбирает аргументы операции, начиная с заданного
+	points := func(from, count int) ([]ecc.Point, error) {
+		if flags.NArg() != from+count {
+			flags.Usage()
+			return nil, fmt.Errorf("%s needs %d arguments", flags.Arg(0), from+count-1)
+		}
+		var result []ecc.Point
+		for _, arg := range flags.Args()[from:] {
+			pt, err := c.ParsePoint(arg)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, pt)
+		}
+		return result, nil
+	}
+
+	switch flags.Arg(0) {
+	case "points":
+		all, err := c.Points()
+		if err != nil {
+			return err
+		}
+		for _, pt := range all {
+			fmt.Println(pt)
+		}
+		fmt.Printf("%d points\n", len(all))
+	case "add":
+		pts, err := points(1, 2)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%v + %v = %v\n", pts[0], pts[1], c.Add(pts[0], pts[1]))
+	case "double":
+		pts, err := points(1, 1)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("2%v = %v\n", pts[0], c.Double(pts[0]))
+	case "mul":
+		pts, err := points(2, 1)
+		if err != nil {
+			return err
+		}
+		k, ok := new(big.Int).SetString(flags.Arg(1), 0)
+		if !ok {
+			return fmt.Errorf("scalar must be a number: %q", flags.Arg(1))
+		}
+		fmt.Printf("%v%v = %v\n", k, pts[0], c.ScalarMult(pts[0], k))
+	default:
+		flags.Usage()
+		return fmt.Errorf("unknown ecc operation %q", flags.Arg(0))
+	}
+	return nil
+}
+
+// serveCommand runs the HTTP JSON API until the process is interrupted
+func serveCommand(args []string) error {
+	flags := flag.NewFlagSet("serve", flag.ContinueOnError)
+	addr := flags.String("addr", "localhost:8080", "address to listen on")
+	maxBody := flags.Int64("max-body", server.DefaultMaxBody, "largest accepted request body in bytes")
+	timeout := flags.Duration("timeout", 30*time.Second, "time limit of one request")
+	webDir := flags.String("web", "", "directory with cipher.wasm and wasm_exec.js, the page using them is served at /")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: serve [-addr host:port] [-max-body bytes] [-timeout duration] [-web dir]")
+		flags.PrintDefaults()
+	}
+
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+	if flags.NArg() != 0 {
+		flags.Usage()
+		return fmt.Errorf("serve takes no arguments")
+	}
+
+	// шаги не собираются: запросы выполняются одновременно, а среди шагов есть секретные значения ключей
+	trace.SetTracer(nil)
+
+	mux := http.NewServeMux()
+	mux.Handle("/v1/", server.New(*maxBody))
+	if *webDir != "" {
+		mux.Handle("/", server.Page(*webDir))
+	}
+
+	srv := &http.Server{
+		Addr:              *addr,
+		Handler:           http.TimeoutHandler(mux, *timeout, `{"error":"request timed out"}`),
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       *timeout,
+		WriteTimeout:      *timeout + 5*time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- srv.ListenAndServe()
+	}()
+	log.Printf("listening on http://%s", *addr)
+
+	select {
+	case err := <-serveErr:
+		return err
+	case <-ctx.Done():
+	}
+
+	// новые соединения не принимаются, начатые запросы завершаются
+	log.Println("shutting down")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+	return srv.Shutdown(shutdownCtx)
+}
+
+// encryptCommand encrypts the text file and writes a container whose header records the cipher,
+// the alphabet, the key fingerprint and the padding. With -key the key, its cipher and its alphabet
+// are taken from the keyring and the cipher argument may be left out
+func encryptCommand(args []string) error {
+	flags := flag.NewFlagSet("encrypt", flag.ContinueOnError)
+	keyName := flags.String("key", "", "name of the key in the keyring")
+	keyringPath := flags.String("keyring", keyringFile, "keyring file")
+	keyPath := flags.String("key-file", keyFile, "file with the key, read when -key is not given")
+	alphabetPath := flags.String("alphabet", alphabetFile, "file with the alphabet, read when -key is not given")
+	inPath := flags.String("in", textFile, "file with the text")
+	outPath := flags.String("o", encryptFile, "file the container is written to")
+	hashOnly := flags.Bool("hash-alphabet", false, "store only the SHA-256 of the alphabet, decryption then needs the same alphabet")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: encrypt [-key name | -key-file file -alphabet file] [-in file] [-o file] [-hash-alphabet] cipher")
+		flags.PrintDefaults()
+	}
+
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+	if flags.NArg() > 1 || flags.NArg() == 0 && *keyName == "" {
+		flags.Usage()
+		return fmt.Errorf("encrypt needs the cipher")
+	}
+
+	var cipherID, alphabet, key string
+	if *keyName != "" {
+		entry, err := keyringEntry(*keyringPath, *keyName)
+		if err != nil {
+			return err
+		}
+		if flags.NArg() == 1 && flags.Arg(0) != entry.Cipher {
+			return fmt.Errorf("key %s belongs to %s, not to %s", entry.Name, entry.Cipher, flags.Arg(0))
+		}
+		cipherID, alphabet, key = entry.Cipher, entry.Alphabet, entry.Key
+	} else {
+		cipherID = flags.Arg(0)
+		alphabet, err = openAndExtractText(*alphabetPath)
+		if err != nil {
+			return err
+		}
+		key, err = openAndExtractText(*keyPath)
+		if err != nil {
+			return err
+		}
+	}
+
+	c, ok := registry.Lookup(cipherID)
+	if !ok || c.Encrypt == nil {
+		return fmt.Errorf("unknown cipher %q", cipherID)
+	}
+	text, err := openAndExtractText(*inPath)
+	if err != nil {
+		return err
+	}
+	if key == "" {
+		return fmt.Errorf("key can not be empty")
+	}
+
+	// ключ вида new|... генерируется и сохраняется, как в меню; в связке ключей хранятся готовые ключи
+	if c.NewKey != nil && *keyName == "" {
+		alphabetMap, err := verify.Alphabet(alphabet)
+		if err != nil {
+			return err
+		}
+		generatedKey, generated, err := c.NewKey(context.Background(), key, alphabetMap, utf8.RuneCountInString(alphabet))
+		if err != nil {
+			return err
+		}
+		if generated {
+			key = generatedKey
+			WriteToFile(*keyPath, key)
+			fmt.Printf("generated key saved to %s\n", *keyPath)
+		}
+	}
+
+	ct, err := container.Seal(c, alphabet, key, text, !*hashOnly)
+	if err != nil {
+		return err
+	}
+	err = os.WriteFile(*outPath, ct.Marshal(), 0644)
+	if err != nil {
+		return err
+	}
+	if ct.KeyFingerprint != "" {
+		fmt.Printf("container saved to %s, key fingerprint %s\n", *outPath, ct.KeyFingerprint)
+	} else {
+		fmt.Printf("container saved to %s\n", *outPath)
+	}
+	return nil
+}
+
+// decryptCommand reads a container made by encrypt and decrypts it with the cipher named in its header.
+// Without -key the keyring is searched for the key with the fingerprint from the header or, when the header
+// has no fingerprint, for the only key of the cipher and the alphabet; then the key file is used
+func decryptCommand(args []string) error {
+	flags := flag.NewFlagSet("decrypt", flag.ContinueOnError)
+	keyName := flags.String("key", "", "name of the key in the keyring")
+	keyringPath := flags.String("keyring", keyringFile, "keyring file")
+	keyPath := flags.String("key-file", keyFile, "file with the key, read when the keyring has no matching key")
+	alphabetPath := flags.String("alphabet", alphabetFile, "file with the alphabet, read only when the container holds just its hash")
+	outPath := flags.String("o", decryptFile, "file the text is written to")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: decrypt [-key name | -key-file file] [-alphabet file] [-o file] [container]")
+		flags.PrintDefaults()
+	}
+
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+	if flags.NArg() > 1 {
+		flags.Usage()
+		return fmt.Errorf("decrypt takes one container")
+	}
+	inPath := encryptFile
+	if flags.NArg() == 1 {
+		inPath = flags.Arg(0)
+	}
+
+	data, err := os.ReadFile(inPath)
+	if err != nil {
+		return err
+	}
+	ct, err := container.Parse(data)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("cipher %s, padding %s, length %d, created %s\n", ct.Cipher, ct.Padding, ct.Length, ct.Created.Format(time.RFC3339))
+
+	var alphabet, key string
+	switch {
+	case *keyName != "":
+		entry, err := keyringEntry(*keyringPath, *keyName)
+		if err != nil {
+			return err
+		}
+		alphabet, key = entry.Alphabet, entry.Key
+	default:
+		ring, err := keyring.Load(*keyringPath)
+		if err != nil {
+			return err
+		}
+		if entry, ok := containerKey(ring, ct); ok {
+			fmt.Printf("using key %s from %s\n", entry.Name, *keyringPath)
+			alphabet, key = entry.Alphabet, entry.Key
+			break
+		}
+
+		if ct.Alphabet == "" {
+			alphabet, err = openAndExtractText(*alphabetPath)
+			if err != nil {
+				return err
+			}
+		}
+		key, err = openAndExtractText(*keyPath)
+		if err != nil {
+			return err
+		}
+	}
+
+	text, err := container.Open(ct, alphabet, key)
+	if err != nil {
+		return err
+	}
+	WriteToFile(*outPath, text)
+	fmt.Printf("text saved to %s\n", *outPath)
+	return nil
+}
+
+// containerKey finds the key of the container in the keyring: by the fingerprint when the header has it,
+// otherwise the key is taken only when it is the single key of the cipher and the alphabet
+func containerKey(ring *keyring.Keyring, ct container.Container) (keyring.Entry, bool) {
+	if ct.KeyFingerprint != "" {
+		entry, err := ring.ByFingerprint(ct.KeyFingerprint)
+		return entry, err == nil
+	}
+
+	var found []keyring.Entry
+	for _, e := range ring.List() {
+		if e.Check() == nil && e.Cipher == ct.Cipher && container.AlphabetHash(e.Alphabet) == ct.AlphabetSHA256 {
+			found = append(found, e)
+		}
+	}
+	if len(found) != 1 {
+		return keyring.Entry{}, false
+	}
+	return found[0], true
+}
+
+// keyringEntry loads the keyring and finds the key in it
+func keyringEntry(path, name string) (keyring.Entry, error) {
+	ring, err := keyring.Load(path)
+	if err != nil {
+		return keyring.Entry{}, err
+	}
+	return ring.Get(name)
+}
+
+// keysCommand manages the named keys of the keyring
+func keysCommand(args []string) error {
+	flags := flag.NewFlagSet("keys", flag.ContinueOnError)
+	keyringPath := flags.String("keyring", keyringFile, "keyring file")
+	alphabetPath := flags.String("alphabet", alphabetFile, "file with the alphabet of an added key")
+	keyPath := flags.String("key-file", keyFile, "file with the key, read by add when the key is not given")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: keys [-keyring file] [-alphabet file] [-key-file file] list | show name | add name cipher [key] | delete name")
+		fmt.Fprintln(flags.Output(), "a key written as new|... is generated before it is added")
+		flags.PrintDefaults()
+	}
+
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return fmt.Errorf("keys needs an operation")
+	}
+
+	// arguments проверяет число аргументов операции
+	arguments := func(from, to int) error {
+		if flags.NArg()-1 < from || flags.NArg()-1 > to {
+			flags.Usage()
+			return fmt.Errorf("wrong number of arguments for %s", flags.Arg(0))
+		}
+		return nil
+	}
+
+	ring, err := keyring.Load(*keyringPath)
+	if err != nil {
+		return err
+	}
+
+	switch flags.Arg(0) {
+	case "list":
+		if err := arguments(0, 0); err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "name\tcipher\tfingerprint\tcreated")
+		var mismatched []string
+		for _, e := range ring.List() {
+			fingerprint := e.Fingerprint
+			if e.Check() != nil {
+				fingerprint += " (mismatch)"
+				mismatched = append(mismatched, e.Name)
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", e.Name, e.Cipher, fingerprint, e.Created.Format(time.RFC3339))
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+		// такие ключи не используются, пока их не удалят и не добавят заново
+		for _, name := range mismatched {
+			fmt.Fprintf(os.Stderr, "warning: key %s does not match its fingerprint and is not used, delete it and add it again\n", name)
+		}
+		return nil
+	case "show":
+		if err := arguments(1, 1); err != nil {
+			return err
+		}
+		e, err := ring.Get(flags.Arg(1))
+		if err != nil {
+			return err
+		}
+		fmt.Printf("name: %s\ncipher: %s\nalphabet: %q\nkey: %s\ncreated: %s\nfingerprint: %s\n",
+			e.Name, e.Cipher, e.Alphabet, e.Key, e.Created.Format(time.RFC3339), e.Fingerprint)
+		return nil
+	case "add":
+		if err := arguments(2, 3); err != nil {
+			return err
+		}
+		c, ok := registry.Lookup(flags.Arg(2))
+		if !ok {
+			return fmt.Errorf("unknown cipher %q", flags.Arg(2))
+		}
+		alphabet, err := openAndExtractText(*alphabetPath)
+		if err != nil {
+			return err
+		}
+		alphabetMap, err := verify.Alphabet(alphabet)
+		if err != nil {
+			return err
+		}
+		key := flags.Arg(3)
+		if flags.NArg() == 3 {
+			key, err = openAndExtractText(*keyPath)
+			if err != nil {
+				return err
+			}
+		}
+
+		if c.NewKey != nil {
+			generatedKey, generated, err := c.NewKey(context.Background(), key, alphabetMap, utf8.RuneCountInString(alphabet))
+			if err != nil {
+				return err
+			}
+			if generated {
+				key = generatedKey
+			}
+		}
+
+		e, err := ring.Add(flags.Arg(1), c.ID, alphabet, key)
+		if err != nil {
+			return err
+		}
+		if err := ring.Save(*keyringPath); err != nil {
+			return err
+		}
+		fmt.Printf("key %s added to %s, fingerprint %s\n", e.Name, *keyringPath, e.Fingerprint)
+		return nil
+	case "delete":
+		if err := arguments(1, 1); err != nil {
+			return err
+		}
+		if err := ring.Delete(flags.Arg(1)); err != nil {
+			return err
+		}
+		if err := ring.Save(*keyringPath); err != nil {
+			return err
+		}
+		fmt.Printf("key %s deleted from %s\n", flags.Arg(1), *keyringPath)
+		return nil
+	}
+
+	flags.Usage()
+	return fmt.Errorf("unknown keys operation %q", flags.Arg(0))
+}
+
+// deriveCommand derives a classical key from a passphrase read from the standard input.
+// The same passphrase, salt and number of iterations always give the same key
+func deriveCommand(args []string) error {
+	flags := flag.NewFlagSet("derive", flag.ContinueOnError)
+	salt := flags.String("salt", "", fmt.Sprintf("salt shared with the passphrase, at least %d bytes", kdf.MinSaltLength))
+	iterations := flags.Int("iter", kdf.DefaultIterations, "number of PBKDF2 iterations")
+	length := flags.Int("length", 8, "keyword length for permutation and vigenere")
+	alphabetPath := flags.String("alphabet", alphabetFile, "file with the alphabet")
+	outPath := flags.String("o", keyFile, "file the key is written to")
+	name := flags.String("add", "", "add the key to the keyring under this name instead of writing the key file")
+	keyringPath := flags.String("keyring", keyringFile, "keyring file")
+	flags.Usage = func() {
+		var ids []string
+		for id := range keygen.Derivers {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		fmt.Fprintln(flags.Output(), "usage: derive -salt salt [-iter n] [-length n] [-alphabet file] [-o file | -add name] cipher")
+		fmt.Fprintf(flags.Output(), "ciphers: %s\n", strings.Join(ids, ", "))
+		flags.PrintDefaults()
+	}
+
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return fmt.Errorf("derive needs the cipher")
+	}
+	derive, ok := keygen.Derivers[flags.Arg(0)]
+	if !ok {
+		flags.Usage()
+		return fmt.Errorf("keys of %q can not be derived", flags.Arg(0))
+	}
+	if len(*salt) < kdf.MinSaltLength {
+		return fmt.Errorf("salt must have at least %d bytes", kdf.MinSaltLength)
+	}
+	if *iterations < 1 {
+		return fmt.Errorf("number of iterations must be positive")
+	}
+
+	alphabet, err := openAndExtractText(*alphabetPath)
+	if err != nil {
+		return err
+	}
+	alphabetMap, err := verify.Alphabet(alphabet)
+	if err != nil {
+		return err
+	}
+	power := utf8.RuneCountInString(alphabet)
+
+	passphrase, err := tui.ReadPassword("passphrase: ")
+	if err != nil {
+		return fmt.Errorf("reading the passphrase: %v", err)
+	}
+	if passphrase == "" {
+		return fmt.Errorf("passphrase can not be empty")
+	}
+
+	// у каждого шифра свой поток чисел, поэтому ключи разных шифров из одной фразы не связаны
+	seed := kdf.Seed(passphrase, *salt, *iterations)
+	key, err := derive(kdf.NewStream(seed, flags.Arg(0)), *length, alphabetMap, power)
+	if err != nil {
+		return err
+	}
+
+	if *name != "" {
+		ring, err := keyring.Load(*keyringPath)
+		if err != nil {
+			return err
+		}
+		e, err := ring.Add(*name, flags.Arg(0), alphabet, key)
+		if err != nil {
+			return err
+		}
+		if err := ring.Save(*keyringPath); err != nil {
+			return err
+		}
+		fmt.Printf("key %s added to %s, fingerprint %s\n", e.Name, *keyringPath, e.Fingerprint)
+		return nil
+	}
+
+	WriteToFile(*outPath, key)
+	fmt.Printf("key saved to %s, fingerprint %s\n", *outPath, keys.Fingerprint(flags.Arg(0), alphabet, key))
+	return nil
+}
+
+// auditCommand reports the key space, the weak properties of the key and the unicity distance
+func auditCommand(args []string) error {
+	flags := flag.NewFlagSet("audit", flag.ContinueOnError)
+	keyName := flags.String("key", "", "name of the key in the keyring")
+	keyringPath := flags.String("keyring", keyringFile, "keyring file")
+	keyPath := flags.String("key-file", keyFile, "file with the key, read when -key is not given")
+	alphabetPath := flags.String("alphabet", alphabetFile, "file with the alphabet, read when -key is not given")
+	inPath := flags.String("in", textFile, "file with the text, its length is compared with the unicity distance")
+	flags.Usage = func() {
+		var ids []string
+		for id := range audit.Auditors {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		fmt.Fprintln(flags.Output(), "usage: audit [-key name | -key-file file -alphabet file] [-in file] cipher")
+		fmt.Fprintf(flags.Output(), "ciphers: %s\n", strings.Join(ids, ", "))
+		flags.PrintDefaults()
+	}
+
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+	if flags.NArg() > 1 || flags.NArg() == 0 && *keyName == "" {
+		flags.Usage()
+		return fmt.Errorf("audit needs the cipher")
+	}
+
+	var cipherID, alphabet, key string
+	if *keyName != "" {
+		entry, err := keyringEntry(*keyringPath, *keyName)
+		if err != nil {
+			return err
+		}
+		if flags.NArg() == 1 && flags.Arg(0) != entry.Cipher {
+			return fmt.Errorf("key %s belongs to %s, not to %s", entry.Name, entry.Cipher, flags.Arg(0))
+		}
+		cipherID, alphabet, key = entry.Cipher, entry.Alphabet, entry.Key
+	} else {
+		cipherID = flags.Arg(0)
+		alphabet, err = openAndExtractText(*alphabetPath)
+		if err != nil {
+			return err
+		}
+		key, err = openAndExtractText(*keyPath)
+		if err != nil {
+			return err
+		}
+	}
+
+	auditor, ok := audit.Auditors[cipherID]
+	if !ok {
+		flags.Usage()
+		return fmt.Errorf("keys of %q can not be audited", cipherID)
+	}
+	alphabetMap, err := verify.Alphabet(alphabet)
+	if err != nil {
+		return err
+	}
+	power := utf8.RuneCountInString(alphabet)
+
+	report, err := auditor(key, alphabetMap, power)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("cipher: %s\n", report.Cipher)
+	fmt.Printf("key space: %v keys (%.1f bits)\n", report.KeySpace, report.Bits())
+	unicity := report.UnicityDistance(power)
+	if math.IsInf(unicity, 1) {
+		fmt.Printf("unicity distance: not defined, an alphabet of %d symbols has no redundancy\n", power)
+	} else {
+		fmt.Printf("unicity distance: %.1f symbols of ciphertext\n", unicity)
+
+		// длина текста сравнивается с расстоянием единственности, если файл есть
+		if text, err := openAndExtractText(*inPath); err == nil && text != "" {
+			n := utf8.RuneCountInString(text)
+			if float64(n) >= unicity {
+				fmt.Printf("text of %d symbols is %.1f times longer: its ciphertext determines the key uniquely\n", n, float64(n)/unicity)
+			} else {
+				fmt.Printf("text of %d symbols is shorter: several keys give a meaningful plaintext\n", n)
+			}
+		}
+	}
+
+	if len(report.Weak) == 0 {
+		fmt.Println("no weak properties found")
+	}
+	for _, w := range report.Weak {
+		fmt.Printf("weak: %s\n", w)
+	}
+	for _, n := range report.Notes {
+		fmt.Printf("note: %s\n", n)
+	}
+	return nil
+}
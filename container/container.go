@@ -0,0 +1,317 @@
+// Package container wraps a ciphertext in a file with a header that records how it was made,
+// so the file can be decrypted without remembering the cipher, the alphabet or the padding:
+//
+//	CIPHER-CONTAINER/2
+//	cipher: magma
+//	alphabet: 0JDQkdCS0JMg
+//	alphabet-sha256: 5f1c...
+//	key-fingerprint: 3a7d0c1e9b2f4a60
+//	padding: gost-34.13-2
+//	length: 22
+//	created: 2026-10-19T11:51:48Z
+//
+//	ciphertext
+//
+// Ciphers with registry.Cipher.Files also record the offset of the pad segment, "pad-offset: 120",
+// the key given to Open then only has to name the pad.
+// The alphabet is written in base64 because it may end with a space, it is left out when
+// only the hash of the alphabet is stored. The key fingerprint is written only for ciphers
+// with registry.Cipher.Fingerprinted: the key of a classical cipher would be found by trying
+// every key against it. The length counts the symbols of the plaintext (bytes for ciphers reading bytes).
+// Version 1 wrote the fingerprint for every cipher.
+package container
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/marelinaa/cipher-algorithms/keys"
+	"github.com/marelinaa/cipher-algorithms/registry"
+	"github.com/marelinaa/cipher-algorithms/verify"
+)
+
+const (
+	// Magic starts every container
+	Magic = "CIPHER-CONTAINER"
+	// Version is the version of the format written by Marshal
+	Version = 2
+)
+
+var (
+	// ErrFormat is returned when the data is not a container or its header is damaged
+	ErrFormat = errors.New("not a valid container")
+	// ErrAlphabetMismatch is returned when the alphabet differs from the one the container was made with
+	ErrAlphabetMismatch = errors.New("alphabet does not match the container")
+	// ErrKeyMismatch is returned when the key differs from the one the container was made with,
+	// it is found only for containers that record the key fingerprint
+	ErrKeyMismatch = errors.New("key does not match the container")
+)
+
+// Header describes how the ciphertext was made
+type Header struct {
+	Version        int
+	Cipher         string
+	Alphabet       string // empty when only the hash is stored
+	AlphabetSHA256 string // hex
+	KeyFingerprint string // keys.Fingerprint of the key, empty when it is not recorded
+	Padding        registry.Padding
+	Length         int
+	Created        time.Time
+	PadOffset      int // offset of the pad segment for ciphers with registry.Cipher.Files, -1 for other ciphers
+}
+
+// Container is the header and the ciphertext
+type Container struct {
+	Header
+	Ciphertext string
+}
+
+// AlphabetHash returns the hex SHA-256 of the alphabet written in the header
+func AlphabetHash(alphabet string) string {
+	sum := sha256.Sum256([]byte(alphabet))
+	return hex.EncodeToString(sum[:])
+}
+
+// Seal encrypts the text and records the header. When storeAlphabet is false only the hash
+// of the alphabet is written and the same alphabet has to be given to Open
+func Seal(c registry.Cipher, alphabet, key, text string, storeAlphabet bool) (Container, error) {
+	if c.Encrypt == nil {
+		return Container{}, fmt.Errorf("%s can only sign, it does not encrypt", c.ID)
+	}
+	alphabetMap, power, err := prepare(alphabet)
+	if err != nil {
+		return Container{}, err
+	}
+	if c.Plaintext == registry.Alphabet {
+		if err := verify.Text(text, alphabetMap); err != nil {
+			return Container{}, err
+		}
+	}
+
+	ciphertext, err := c.Encrypt(text, key, alphabetMap, power)
+	if err != nil {
+		return Container{}, err
+	}
+
+	h := Header{
+		Version:        Version,
+		Cipher:         c.ID,
+		AlphabetSHA256: AlphabetHash(alphabet),
+		Padding:        c.PaddingOf(key),
+		Length:         length(text, c.Plaintext),
+		Created:        time.Now().UTC().Truncate(time.Second),
+		PadOffset:      -1,
+	}
+	if c.Files {
+		// ключ блокнота указывает и файл, и отрезок; без смещения отрезка контейнер не расшифровать
+		pk, err := verify.PadKey(key, true)
+		if err != nil {
+			return Container{}, err
+		}
+		h.PadOffset = pk.Offset
+	}
+	if c.Fingerprinted {
+		h.KeyFingerprint = keys.Fingerprint(c.ID, alphabet, key)
+	}
+	if storeAlphabet {
+		h.Alphabet = alphabet
+	}
+	return Container{Header: h, Ciphertext: ciphertext}, nil
+}
+
+// Open decrypts the container with the cipher named in the header. The alphabet is taken
+// from the header, the given one is used only when the header holds just its hash.
+// The pad offset from the header replaces the offset written in a pad key
+func Open(ct Container, alphabet, key string) (string, error) {
+	c, ok := registry.Lookup(ct.Cipher)
+	if !ok || c.Decrypt == nil {
+		return "", fmt.Errorf("container is encrypted with an unknown cipher %q", ct.Cipher)
+	}
+
+	if ct.Alphabet != "" {
+		alphabet = ct.Alphabet
+	}
+	if AlphabetHash(alphabet) != ct.AlphabetSHA256 {
+		return "", ErrAlphabetMismatch
+	}
+	if ct.KeyFingerprint != "" && keys.Fingerprint(c.ID, alphabet, key) != ct.KeyFingerprint {
+		return "", fmt.Errorf("%w: its fingerprint is %s", ErrKeyMismatch, ct.KeyFingerprint)
+	}
+
+	if c.Files {
+		if ct.PadOffset < 0 {
+			return "", fmt.Errorf("%w: field pad-offset is missing", ErrFormat)
+		}
+		pk, err := verify.PadKey(key, false)
+		if err != nil {
+			return "", err
+		}
+		key = fmt.Sprintf("%s%s%d", pk.Path, keys.Delimiter, ct.PadOffset)
+	}
+
+	alphabetMap, power, err := prepare(alphabet)
+	if err != nil {
+		return "", err
+	}
+	text, err := c.Decrypt(ct.Ciphertext, key, alphabetMap, power)
+	if err != nil {
+		return "", err
+	}
+
+	// случайные символы дополнения отбрасываются по исходной длине
+	n := length(text, c.Plaintext)
+	switch {
+	case n > ct.Length && ct.Padding == registry.RandomPadding:
+		if c.Plaintext == registry.Bytes {
+			return text[:ct.Length], nil
+		}
+		return string([]rune(text)[:ct.Length]), nil
+	case n != ct.Length:
+		return "", fmt.Errorf("%w: decrypted text has length %d, the header records %d", ErrFormat, n, ct.Length)
+	}
+	return text, nil
+}
+
+// Marshal writes the container in its text format
+func (ct Container) Marshal() []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "%s/%d\n", Magic, ct.Version)
+	fmt.Fprintf(&b, "cipher: %s\n", ct.Cipher)
+	if ct.Alphabet != "" {
+		fmt.Fprintf(&b, "alphabet: %s\n", base64.StdEncoding.EncodeToString([]byte(ct.Alphabet)))
+	}
+	fmt.Fprintf(&b, "alphabet-sha256: %s\n", ct.AlphabetSHA256)
+	if ct.KeyFingerprint != "" {
+		fmt.Fprintf(&b, "key-fingerprint: %s\n", ct.KeyFingerprint)
+	}
+	fmt.Fprintf(&b, "padding: %s\n", ct.Padding)
+	fmt.Fprintf(&b, "length: %d\n", ct.Length)
+	fmt.Fprintf(&b, "created: %s\n", ct.Created.Format(time.RFC3339))
+	if ct.PadOffset >= 0 {
+		fmt.Fprintf(&b, "pad-offset: %d\n", ct.PadOffset)
+	}
+	fmt.Fprintf(&b, "\n%s\n", ct.Ciphertext)
+	return b.Bytes()
+}
+
+// Parse reads a container written by Marshal
+func Parse(data []byte) (Container, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, max(len(data)+1, bufio.MaxScanTokenSize))
+
+	if !scanner.Scan() {
+		return Container{}, fmt.Errorf("%w: the file is empty", ErrFormat)
+	}
+	magic, version, ok := strings.Cut(scanner.Text(), "/")
+	if !ok || magic != Magic {
+		return Container{}, fmt.Errorf("%w: the file does not start with %s", ErrFormat, Magic)
+	}
+
+	ct := Container{Header: Header{PadOffset: -1}}
+	var err error
+	ct.Version, err = strconv.Atoi(version)
+	if err != nil {
+		return Container{}, fmt.Errorf("%w: version %q", ErrFormat, version)
+	}
+	if ct.Version < 1 || ct.Version > Version {
+		return Container{}, fmt.Errorf("%w: version %d is not supported, the latest is %d", ErrFormat, ct.Version, Version)
+	}
+
+	// поля заголовка до пустой строки, затем шифртекст
+	seen := make(map[string]bool)
+	for scanner.Scan() && scanner.Text() != "" {
+		name, value, ok := strings.Cut(scanner.Text(), ": ")
+		if !ok {
+			return Container{}, fmt.Errorf("%w: header line %q", ErrFormat, scanner.Text())
+		}
+		if seen[name] {
+			return Container{}, fmt.Errorf("%w: field %s is repeated", ErrFormat, name)
+		}
+		seen[name] = true
+
+		if err := ct.set(name, value); err != nil {
+			return Container{}, fmt.Errorf("%w: %s: %v", ErrFormat, name, err)
+		}
+	}
+	required := []string{"cipher", "alphabet-sha256", "padding", "length", "created"}
+	if ct.Version == 1 {
+		required = append(required, "key-fingerprint")
+	}
+	for _, name := range required {
+		if !seen[name] {
+			return Container{}, fmt.Errorf("%w: field %s is missing", ErrFormat, name)
+		}
+	}
+
+	var lines []string
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return Container{}, err
+	}
+	ct.Ciphertext = strings.Join(lines, "\n")
+	return ct, nil
+}
+
+func (ct *Container) set(name, value string) error {
+	var err error
+	switch name {
+	case "cipher":
+		ct.Cipher = value
+	case "alphabet":
+		var alphabet []byte
+		alphabet, err = base64.StdEncoding.DecodeString(value)
+		ct.Alphabet = string(alphabet)
+	case "alphabet-sha256":
+		ct.AlphabetSHA256 = value
+	case "key-fingerprint":
+		ct.KeyFingerprint = value
+	case "padding":
+		ct.Padding = registry.Padding(value)
+	case "length":
+		ct.Length, err = strconv.Atoi(value)
+		if err == nil && ct.Length < 0 {
+			err = errors.New("length can not be negative")
+		}
+	case "created":
+		ct.Created, err = time.Parse(time.RFC3339, value)
+	case "pad-offset":
+		ct.PadOffset, err = strconv.Atoi(value)
+		if err == nil && ct.PadOffset < 0 {
+			err = errors.New("offset can not be negative")
+		}
+	default:
+		err = errors.New("unknown field")
+	}
+	return err
+}
+
+// prepare checks the alphabet and returns its map and power
+func prepare(alphabet string) (map[rune]int, int, error) {
+	if alphabet == "" {
+		return nil, 0, errors.New("alphabet can not be empty")
+	}
+	alphabetMap, err := verify.Alphabet(alphabet)
+	if err != nil {
+		return nil, 0, err
+	}
+	return alphabetMap, utf8.RuneCountInString(alphabet), nil
+}
+
+// length returns the length of the plaintext in the units the cipher reads
+func length(text string, format registry.Format) int {
+	if format == registry.Bytes {
+		return len(text)
+	}
+	return utf8.RuneCountInString(text)
+}
@@ -0,0 +1,113 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/marelinaa/cipher-algorithms/registry"
+	"github.com/marelinaa/cipher-algorithms/verify"
+)
+
+const alphabet = "АБВГДЕЁЖЗИЙКЛМНОПРСТУФХЦЧШЩЪЫЬЭЮЯ "
+
+func lookup(t *testing.T, id string) registry.Cipher {
+	t.Helper()
+	c, ok := registry.Lookup(id)
+	if !ok {
+		t.Fatalf("cipher %s is not registered", id)
+	}
+	return c
+}
+
+// roundTrip seals the text, writes and reads the container and opens it with the key
+func roundTrip(t *testing.T, c registry.Cipher, sealKey, openKey, text string) Container {
+	t.Helper()
+	sealed, err := Seal(c, alphabet, sealKey, text, true)
+	if err != nil {
+		t.Fatalf("Seal: %v", err)
+	}
+	parsed, err := Parse(sealed.Marshal())
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if parsed.Header != sealed.Header {
+		t.Errorf("parsed header = %+v, want %+v", parsed.Header, sealed.Header)
+	}
+
+	got, err := Open(parsed, "", openKey)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if got != text {
+		t.Errorf("Open = %q, want %q", got, text)
+	}
+	return parsed
+}
+
+func TestBlockModeRoundTrip(t *testing.T) {
+	c := lookup(t, "magma")
+	key := "ffeeddccbbaa99887766554433221100f0f1f2f3f4f5f6f7f8f9fafbfcfdfeff|cbc"
+	ct := roundTrip(t, c, key, key, "text that does not fill whole blocks")
+
+	if ct.Padding != registry.GOSTPadding {
+		t.Errorf("padding = %s, want %s", ct.Padding, registry.GOSTPadding)
+	}
+	if ct.KeyFingerprint == "" {
+		t.Error("fingerprint of a magma key is not recorded")
+	}
+	if ct.PadOffset != -1 {
+		t.Errorf("pad offset = %d, want -1", ct.PadOffset)
+	}
+
+	other := "00112233445566778899aabbccddeeff00112233445566778899aabbccddeeff|cbc"
+	if _, err := Open(ct, "", other); !errors.Is(err, ErrKeyMismatch) {
+		t.Errorf("Open with another key: err = %v, want ErrKeyMismatch", err)
+	}
+}
+
+func TestPadRoundTrip(t *testing.T) {
+	alphabetMap, err := verify.Alphabet(alphabet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	power := utf8.RuneCountInString(alphabet)
+
+	for _, id := range []string{"otp", "xor"} {
+		c := lookup(t, id)
+		path := filepath.Join(t.TempDir(), "pad")
+		if _, _, err := c.NewKey(context.Background(), "new|"+path+"|100", alphabetMap, power); err != nil {
+			t.Fatal(err)
+		}
+
+		// второй контейнер берёт следующий отрезок блокнота, открывается он по пути к блокноту
+		var offsets []int
+		for _, text := range []string{"ПЕРВОЕ", "ВТОРОЕ СООБЩЕНИЕ"} {
+			key, _, err := c.NewKey(context.Background(), path, alphabetMap, power)
+			if err != nil {
+				t.Fatal(err)
+			}
+			offsets = append(offsets, roundTrip(t, c, key, path, text).PadOffset)
+		}
+		if offsets[0] != 0 || offsets[1] <= offsets[0] {
+			t.Errorf("%s: pad offsets = %v, want 0 and then the end of the first segment", id, offsets)
+		}
+	}
+}
+
+func TestPadOffsetMissing(t *testing.T) {
+	ct := Container{Header: Header{
+		Version:        Version,
+		Cipher:         "otp",
+		Alphabet:       alphabet,
+		AlphabetSHA256: AlphabetHash(alphabet),
+		Padding:        registry.NoPadding,
+		Length:         1,
+		PadOffset:      -1,
+	}, Ciphertext: "А"}
+	if _, err := Open(ct, "", "pad"); !errors.Is(err, ErrFormat) {
+		t.Errorf("Open without pad offset: err = %v, want ErrFormat", err)
+	}
+}
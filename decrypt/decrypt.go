@@ -1,34 +1,34 @@
 package decrypt
 
 import (
-	"errors"
 	"fmt"
-	"log"
 	"sort"
+	"strconv"
 	"strings"
 	"unicode/utf8"
 
 	"github.com/marelinaa/cipher-algorithms/encrypt"
 	"github.com/marelinaa/cipher-algorithms/keys"
+	"github.com/marelinaa/cipher-algorithms/modmath"
+	"github.com/marelinaa/cipher-algorithms/trace"
 	"golang.org/x/exp/rand"
 )
 
 // Caesar осуществляет дешифрование Цезаря
-func Caesar(input string, key int, alphabetMap map[rune]int, power int) string {
+func Caesar(input string, key int, alphabetMap map[rune]int, power int) (string, error) {
 	return encrypt.Caesar(input, -key, alphabetMap, power)
 }
 
-func modInverse(a, m int) (int, error) {
-	a = a % m
-	for x := 1; x < m; x++ {
-		if (a*x)%m == 1 {
-			return x, nil
-		}
+func Affine(input string, key keys.Affine, alphabetMap map[rune]int, power int) (string, error) {
+	// Find modular inverse of key.K1
+	k1Inverse, err := modmath.Inverse(key.K1, power)
+	if err != nil {
+		return "", fmt.Errorf("K1 has no modular inverse, decryption is not possible: %w", err)
+	}
+	if err := encrypt.CheckText(input, alphabetMap); err != nil {
+		return "", err
 	}
-	return 0, errors.New("no modular inverse exists")
-}
 
-func Affine(input string, key keys.Affine, alphabetMap map[rune]int, power int) string {
 	reverseAlphabetMap := make(map[int]rune)
 
 	// Creating a map: numeric representation -> alphabet character
@@ -36,25 +36,33 @@ func Affine(input string, key keys.Affine, alphabetMap map[rune]int, power int)
 		reverseAlphabetMap[index] = char
 	}
 
-	// Find modular inverse of key.K1
-	k1Inverse, err := modInverse(key.K1, power)
-	if err != nil {
-		panic("K1 has no modular inverse, decryption is not possible!")
+	if trace.Enabled() {
+		trace.Emit(trace.Step{Input: "K1", Formula: "K1^-1 mod power",
+			Values: fmt.Sprintf("%d^-1 mod %d", key.K1, power), Output: fmt.Sprint(k1Inverse)})
 	}
 
 	var decryptedText []rune
 
-	for _, char := range input {
+	for i, char := range []rune(input) {
 		idx := alphabetMap[char]
 		// Decryption formula: P = K1^{-1} * (C - K2) mod power
-		newIdx := (k1Inverse * (idx - key.K2 + power)) % power
+		newIdx := modmath.Mod(k1Inverse*(idx-key.K2), power)
 		decryptedText = append(decryptedText, reverseAlphabetMap[newIdx])
+
+		if trace.Enabled() {
+			trace.Emit(trace.Step{Pos: i + 1, Input: string(char), Formula: "K1^-1*(idx-K2) % power",
+				Values: fmt.Sprintf("%d*(%d-%d) %% %d = %d", k1Inverse, idx, key.K2, power, newIdx), Output: string(reverseAlphabetMap[newIdx])})
+		}
 	}
 
-	return string(decryptedText)
+	return string(decryptedText), nil
 }
 
-func Substitution(input string, key []rune, alphabetMap map[rune]int, power int) string {
+func Substitution(input string, key []rune, alphabetMap map[rune]int, power int) (string, error) {
+	if len(key) != power {
+		return "", fmt.Errorf("%w: substitution key has %d symbols, the alphabet has %d", encrypt.ErrKeyLength, len(key), power)
+	}
+
 	reverseAlphabetMap := make(map[int]rune)
 
 	// Creating a map: numeric representation -> alphabet character
@@ -69,38 +77,53 @@ func Substitution(input string, key []rune, alphabetMap map[rune]int, power int)
 
 	var decryptedText []rune
 
-	for _, char := range input {
+	for pos, char := range []rune(input) {
 		// Check if the character is valid in the reverse key map
-		i := keyMap[char]
-		decryptedChar, ok := reverseAlphabetMap[i]
+		i, ok := keyMap[char]
 		if !ok {
-			fmt.Printf("input contains invalid character: '%c'\n", char)
-			return ""
+			return "", &encrypt.ErrInvalidRune{Rune: char, Pos: pos}
 		}
+		decryptedChar := reverseAlphabetMap[i]
 
 		// Decrypt by replacing the character with the one at the index from the reverse key
 		decryptedText = append(decryptedText, decryptedChar)
+
+		if trace.Enabled() {
+			trace.Emit(trace.Step{Pos: len(decryptedText), Input: string(char), Formula: "idx such that key[idx] = symbol",
+				Values: fmt.Sprintf("key[%d] = %c", i, char), Output: string(decryptedChar)})
+		}
 	}
 
-	return string(decryptedText)
+	return string(decryptedText), nil
 }
 
-func Permutation(input, keyword string, alphabetMap map[rune]int) string {
+func Permutation(input, keyword string, alphabetMap map[rune]int) (string, error) {
 	cols := utf8.RuneCountInString(keyword)
-	rows := utf8.RuneCountInString(input) / cols
+	if cols == 0 {
+		return "", fmt.Errorf("%w: permutation key is empty", encrypt.ErrKeyLength)
+	}
+	if err := encrypt.CheckText(input, alphabetMap); err != nil {
+		return "", err
+	}
 
+	rows := utf8.RuneCountInString(input) / cols
 	if utf8.RuneCountInString(input)%cols != 0 {
-		log.Println("длина шифртекста не кратна длине ключа")
+		return "", fmt.Errorf("ciphertext length %d is not a multiple of the key length %d", utf8.RuneCountInString(input), cols)
 	}
 
 	order := getKeywordOrder(keyword, alphabetMap)
-	fmt.Println("Порядок перестановки:", order)
 
 	reverseOrder := make([]int, len(order))
 	for i, pos := range order {
 		reverseOrder[pos] = i
 	}
-	fmt.Println("Обратный порядок перестановки:", reverseOrder)
+
+	if trace.Enabled() {
+		trace.Emit(trace.Step{Input: keyword, Formula: "order of the key symbols in the alphabet",
+			Values: fmt.Sprintf("%d rows x %d columns", rows, cols), Output: fmt.Sprint(order)})
+		trace.Emit(trace.Step{Input: fmt.Sprint(order), Formula: "reverse order",
+			Values: "reverse[order[i]] = i", Output: fmt.Sprint(reverseOrder)})
+	}
 
 	// Создаем таблицу для расшифровки
 	table := make([][]rune, rows)
@@ -116,7 +139,13 @@ func Permutation(input, keyword string, alphabetMap map[rune]int) string {
 
 	// Восстанавливаем исходный порядок в каждой строке
 	for i := 0; i < rows; i++ {
-		table[i] = rearrangeRow(table[i], reverseOrder)
+		row := table[i]
+		table[i] = rearrangeRow(row, reverseOrder)
+
+		if trace.Enabled() {
+			trace.Emit(trace.Step{Input: string(row), Formula: "row[i] -> column reverse[i]",
+				Values: fmt.Sprintf("row %d", i+1), Output: string(table[i])})
+		}
 	}
 
 	// Собираем исходный текст
@@ -129,7 +158,7 @@ func Permutation(input, keyword string, alphabetMap map[rune]int) string {
 		}
 	}
 
-	return plainText.String()
+	return plainText.String(), nil
 }
 
 // Функция для получения порядка перестановки по алфавиту
@@ -168,29 +197,43 @@ func rearrangeRow(row []rune, order []int) []rune {
 	return rearranged
 }
 
-func Vigenere(ciphertext string, key string, alphabet map[rune]int) string {
+func Vigenere(ciphertext string, key string, alphabet map[rune]int) (string, error) {
+	if key == "" {
+		return "", fmt.Errorf("%w: vigenere key is empty", encrypt.ErrKeyLength)
+	}
+	if err := encrypt.CheckText(ciphertext, alphabet); err != nil {
+		return "", err
+	}
+
 	alphabetLength := len(alphabet)
 	reverseMap := make(map[int]rune)
 	for char, index := range alphabet {
 		reverseMap[index] = char
 	}
 
-	keyLength := len(key)
+	// ключ и текст индексируются по символам, а не по байтам
+	keyRunes := []rune(key)
+	keyLength := len(keyRunes)
 	keyIndices := make([]int, keyLength)
-	for i, char := range key {
+	for i, char := range keyRunes {
 		keyIndices[i] = alphabet[char]
 	}
 
 	decryptedText := make([]rune, 0)
 
-	for i, char := range ciphertext {
+	for i, char := range []rune(ciphertext) {
 		c := alphabet[char]
 		k := keyIndices[i%keyLength]
-		decryptedCharIndex := (c - k + alphabetLength) % alphabetLength
+		decryptedCharIndex := modmath.Mod(c-k, alphabetLength)
 		decryptedText = append(decryptedText, reverseMap[decryptedCharIndex])
+
+		if trace.Enabled() {
+			trace.Emit(trace.Step{Pos: len(decryptedText), Input: string(char), Formula: "(idx-k[i]) % power",
+				Values: fmt.Sprintf("(%d-%d) %% %d = %d", c, k, alphabetLength, decryptedCharIndex), Output: string(reverseMap[decryptedCharIndex])})
+		}
 	}
 
-	return string(decryptedText)
+	return string(decryptedText), nil
 }
 
 func randomRune(alphabetMap map[rune]int, power int) rune {
@@ -205,118 +248,350 @@ func randomRune(alphabetMap map[rune]int, power int) rune {
 	return alphabet[i]
 }
 
-// func determinant2x2(key [2][2]int) int {
-// 	k11 := key[0][0]
-// 	k12 := key[0][1]
-// 	k21 := key[1][0]
-// 	k22 := key[1][1]
+func Hill(input string, key [2][2]int, alphabetMap map[rune]int, power int) (string, error) {
+	inverse, err := modmath.InverseMatrix(key, power)
+	if err != nil {
+		return "", fmt.Errorf("hill key is not invertible: %w", err)
+	}
+	if err := encrypt.CheckText(input, alphabetMap); err != nil {
+		return "", err
+	}
+
+	if utf8.RuneCountInString(input)%2 != 0 {
+		rand := randomRune(alphabetMap, power)
+		input += string(rand) // Добавляем символ для выравнивания
+	}
 
-// 	return (k11*k22 - k12*k21)
-// }
+	if trace.Enabled() {
+		det := modmath.Det(key, power)
+		detInverse, _ := modmath.Inverse(det, power)
+		trace.Emit(trace.Step{Input: "K", Formula: "det = (K11*K22-K12*K21) % power",
+			Values: fmt.Sprintf("(%d*%d-%d*%d) %% %d", key[0][0], key[1][1], key[0][1], key[1][0], power), Output: fmt.Sprint(det)})
+		trace.Emit(trace.Step{Input: "det", Formula: "det^-1 mod power",
+			Values: fmt.Sprintf("%d^-1 mod %d", det, power), Output: fmt.Sprint(detInverse)})
+		trace.Emit(trace.Step{Input: "K", Formula: "K^-1 = det^-1 * [[K22, -K12], [-K21, K11]] % power",
+			Values: fmt.Sprintf("%d * [[%d, %d], [%d, %d]] %% %d", detInverse, key[1][1], -key[0][1], -key[1][0], key[0][0], power),
+			Output: fmt.Sprintf("[[%d, %d], [%d, %d]]", inverse[0][0], inverse[0][1], inverse[1][0], inverse[1][1])})
+	}
 
-func k1Inverse(k1, M int) (int, error) {
-	k1 = k1 % M
-	for i := 1; i < M; i++ {
-		if (k1*i)%M == 1 {
-			return i, nil
+	reverseAlphabetMap := make(map[int]rune)
+	for char, idx := range alphabetMap {
+		reverseAlphabetMap[idx] = char
+	}
+
+	var ciphertext strings.Builder
+	text := []rune(input)
+	for i := 0; i < utf8.RuneCountInString(input); i += 2 {
+		p1 := alphabetMap[text[i]]
+		p2 := alphabetMap[text[i+1]]
+
+		c1, c2 := modmath.MulVector(p1, p2, inverse, power)
+		ciphertext.WriteRune(reverseAlphabetMap[c1])
+		ciphertext.WriteRune(reverseAlphabetMap[c2])
+
+		if trace.Enabled() {
+			trace.Emit(trace.Step{Pos: i + 1, Input: string(text[i : i+2]), Formula: "(p1*K'11+p2*K'21, p1*K'12+p2*K'22) % power",
+				Values: fmt.Sprintf("(%d*%d+%d*%d, %d*%d+%d*%d) %% %d = (%d, %d)", p1, inverse[0][0], p2, inverse[1][0], p1, inverse[0][1], p2, inverse[1][1], power, c1, c2),
+				Output: string([]rune{reverseAlphabetMap[c1], reverseAlphabetMap[c2]})})
 		}
 	}
 
-	return -1, errors.New("no modular inverse found for determinant")
+	return ciphertext.String(), nil
 }
 
-// func inverseMatrix(key [2][2]int, power int) ([2][2]int, error) {
-// 	det := determinant2x2(key)
+func untranspose(input string, ranks []int) string {
+	inputRunes := []rune(input)
+	plainText := make([]rune, len(inputRunes))
+
+	for i, pos := range encrypt.TranspositionOrder(len(inputRunes), ranks) {
+		plainText[pos] = inputRunes[i]
+	}
 
-// 	if det == 0 {
-// 		return [2][2]int{}, errors.New("matrix is singular, no inverse exists")
-// 	}
+	return string(plainText)
+}
 
-// 	// invDet, err := k1Inverse(det, power)
-// 	// if err != nil {
-// 	// 	return [2][2]int{}, err
-// 	// }
+// Columnar осуществляет дешифрование столбцовой перестановки
+func Columnar(input, keyword string, alphabetMap map[rune]int) (string, error) {
+	if keyword == "" {
+		return "", fmt.Errorf("%w: transposition key is empty", encrypt.ErrKeyLength)
+	}
+	if err := encrypt.CheckText(input, alphabetMap); err != nil {
+		return "", err
+	}
 
-// 	a := key[0][0]
-// 	b := key[0][1]
-// 	c := key[1][0]
-// 	d := key[1][1]
+	return untranspose(input, encrypt.KeywordRanks(keyword, alphabetMap)), nil
+}
 
-// 	inv := [2][2]int{
-// 		{Mod(d, power), -Mod(b, power)},
-// 		{-Mod(c, power), Mod(a, power)},
-// 	}
-// 	fmt.Println(inv)
+// Myszkowski осуществляет дешифрование перестановки Мышковского
+func Myszkowski(input, keyword string, alphabetMap map[rune]int) (string, error) {
+	if keyword == "" {
+		return "", fmt.Errorf("%w: transposition key is empty", encrypt.ErrKeyLength)
+	}
+	if err := encrypt.CheckText(input, alphabetMap); err != nil {
+		return "", err
+	}
 
-// 	return inv, nil
-// }
+	ranks := make([]int, 0, utf8.RuneCountInString(keyword))
+	for _, char := range keyword {
+		ranks = append(ranks, alphabetMap[char])
+	}
 
-func Mod(x, y int) int {
-	if x < 0 {
-		a := -x / y
+	return untranspose(input, ranks), nil
+}
 
-		return ((a)+1)*y + x
+// DoubleTransposition осуществляет дешифрование двойной перестановки, ключи применяются в обратном порядке
+func DoubleTransposition(input string, key keys.DoubleTransposition, alphabetMap map[rune]int) (string, error) {
+	second, err := Columnar(input, key.Second, alphabetMap)
+	if err != nil {
+		return "", err
 	}
+	return Columnar(second, key.First, alphabetMap)
+}
 
-	return x - (x/y)*y
+// Enigma осуществляет дешифрование роторной машиной, которое совпадает с шифрованием
+func Enigma(input string, key keys.Enigma, alphabetMap map[rune]int, power int) (string, error) {
+	return encrypt.Enigma(input, key, alphabetMap, power)
 }
 
-// func hillEncryptPair(k11, k12, k21, k22, p1, p2, power int) (int, int) {
-// 	c1 := Mod(k11*p1+k21*p2, power)
-// 	c2 := Mod(k12*p1+k22*p2, power)
+// Homophonic осуществляет дешифрование омофонной замены по обратной таблице
+func Homophonic(input string, key keys.Homophonic) (string, error) {
+	reverseTable := make(map[int]rune)
+	for char, codes := range key.Codes {
+		for _, code := range codes {
+			reverseTable[code] = char
+		}
+	}
+
+	width := key.Width()
+	if len(input)%width != 0 {
+		return "", fmt.Errorf("ciphertext length must be a multiple of %d digits", width)
+	}
 
-// 	return c1, c2
-// }
+	var plainText strings.Builder
+	for i := 0; i < len(input); i += width {
+		code, err := strconv.Atoi(input[i : i+width])
+		if err != nil {
+			return "", fmt.Errorf("ciphertext contains invalid code: %q", input[i:i+width])
+		}
 
-func gcd(a, b int) int {
-	if b == 0 {
-		return a
+		char, ok := reverseTable[code]
+		if !ok {
+			return "", fmt.Errorf("ciphertext contains unknown code: %d", code)
+		}
+		plainText.WriteRune(char)
 	}
-	return gcd(b, a%b)
+
+	return plainText.String(), nil
 }
 
-func Hill(input string, key [2][2]int, alphabetMap map[rune]int, power int) string {
+// Trithemius осуществляет дешифрование Тритемия
+func Trithemius(input string, key keys.Trithemius, alphabetMap map[rune]int, power int) (string, error) {
+	if err := encrypt.CheckText(input, alphabetMap); err != nil {
+		return "", err
+	}
 
-	k11 := key[0][0]
-	k12 := key[0][1]
-	k21 := key[1][0]
-	k22 := key[1][1]
+	reverseAlphabetMap := make(map[int]rune)
+	for char, index := range alphabetMap {
+		reverseAlphabetMap[index] = char
+	}
 
-	detK := ((k11*k22)%power - (k12*k21)%power + power) % power
+	var decryptedText []rune
+	shift := modmath.Mod(key.Shift, power)
+	for i, char := range []rune(input) {
+		idx := alphabetMap[char]
+		newIdx := modmath.Mod(idx-shift, power)
+		decryptedText = append(decryptedText, reverseAlphabetMap[newIdx])
 
-	if detK == 0 || gcd(detK, power) != 1 {
-		return ""
+		if trace.Enabled() {
+			trace.Emit(trace.Step{Pos: i + 1, Input: string(char), Formula: "(idx-shift) % power",
+				Values: fmt.Sprintf("(%d-%d) %% %d = %d", idx, shift, power, newIdx), Output: string(reverseAlphabetMap[newIdx])})
+		}
+		shift = modmath.Mod(shift+key.Step, power)
 	}
 
-	detInverse, err := k1Inverse(detK, power)
-	if err != nil {
-		return ""
+	return string(decryptedText), nil
+}
+
+// Alberti осуществляет дешифрование диска Альберти: первая буква каждой группы задает положение диска
+func Alberti(input string, key keys.Alberti, alphabetMap map[rune]int, power int) (string, error) {
+	reverseAlphabetMap := make(map[int]rune)
+	for char, index := range alphabetMap {
+		reverseAlphabetMap[index] = char
 	}
 
-	if utf8.RuneCountInString(input)%2 != 0 {
-		rand := randomRune(alphabetMap, power)
-		input += string(rand) // Добавляем символ для выравнивания
+	innerMap := make(map[rune]int)
+	for i, char := range key.Inner {
+		innerMap[char] = i
+	}
+	indexPos := innerMap[key.Index]
+
+	var decryptedText []rune
+	offset := 0
+	for i, char := range []rune(input) {
+		if i%(key.Period+1) == 0 {
+			outer, ok := alphabetMap[char]
+			if !ok {
+				return "", &encrypt.ErrInvalidRune{Rune: char, Pos: i}
+			}
+			offset = indexPos - outer
+
+			if trace.Enabled() {
+				trace.Emit(trace.Step{Pos: i + 1, Input: string(char), Formula: "offset = index - outer",
+					Values: fmt.Sprintf("%d-%d = %d", indexPos, outer, offset), Output: string(key.Index)})
+			}
+			continue
+		}
+
+		inner, ok := innerMap[char]
+		if !ok {
+			return "", &encrypt.ErrInvalidRune{Rune: char, Pos: i}
+		}
+		outerPos := modmath.Mod(inner-offset, power)
+		decryptedText = append(decryptedText, reverseAlphabetMap[outerPos])
+
+		if trace.Enabled() {
+			trace.Emit(trace.Step{Pos: i + 1, Input: string(char), Formula: "(inner-offset) % power",
+				Values: fmt.Sprintf("(%d%+d) %% %d = %d", inner, -offset, power, outerPos), Output: string(reverseAlphabetMap[outerPos])})
+		}
+	}
+
+	return string(decryptedText), nil
+}
+
+// Tableau осуществляет дешифрование по таблице, построенной из алфавитов открытого текста и шифротекста
+func Tableau(input string, plain, cipher []rune, indicator string) (string, error) {
+	if indicator == "" {
+		return "", fmt.Errorf("%w: indicator is empty", encrypt.ErrKeyLength)
+	}
+
+	cipherMap := make(map[rune]int)
+	for i, char := range cipher {
+		cipherMap[char] = i
+	}
+
+	power := len(plain)
+	indicatorRunes := []rune(indicator)
+
+	decryptedText := make([]rune, 0, utf8.RuneCountInString(input))
+	for i, char := range []rune(input) {
+		idx, ok := cipherMap[char]
+		if !ok {
+			return "", &encrypt.ErrInvalidRune{Rune: char, Pos: i}
+		}
+		shift := cipherMap[indicatorRunes[i%len(indicatorRunes)]]
+		decryptedText = append(decryptedText, plain[modmath.Mod(idx-shift, power)])
+	}
+
+	return string(decryptedText), nil
+}
+
+// Quagmire осуществляет дешифрование Quagmire I-IV
+func Quagmire(input string, key keys.Quagmire) (string, error) {
+	return Tableau(input, key.Plain, key.Cipher, key.Indicator)
+}
+
+// Porta осуществляет дешифрование Порта, которое совпадает с шифрованием
+func Porta(input, keyword string, alphabetMap map[rune]int, power int) (string, error) {
+	return encrypt.Porta(input, keyword, alphabetMap, power)
+}
+
+// Checkerboard восстанавливает текст из потока цифр
+func Checkerboard(input string, board keys.Checkerboard) (string, error) {
+	rowOf := make(map[int]int)
+	for i, b := range board.Blanks {
+		rowOf[b] = i + 1
+	}
+
+	var plainText strings.Builder
+	for i := 0; i < len(input); i++ {
+		d := int(input[i] - '0')
+		if d < 0 || d > 9 {
+			return "", nonDigit(input, i)
+		}
+
+		char := board.Rows[0][d]
+		if row, ok := rowOf[d]; ok {
+			i++
+			if i == len(input) {
+				return "", fmt.Errorf("ciphertext ends in the middle of a two-digit code")
+			}
+			col := int(input[i] - '0')
+			if col < 0 || col > 9 {
+				return "", nonDigit(input, i)
+			}
+			char = board.Rows[row][col]
+		}
+
+		if char == 0 {
+			return "", fmt.Errorf("ciphertext contains a code outside of the checkerboard at position %d", i)
+		}
+		plainText.WriteRune(char)
+	}
+
+	return plainText.String(), nil
+}
+
+// nonDigit reports the symbol at byte offset i of the digit stream, all bytes before it are digits,
+// so the offset is also its position
+func nonDigit(input string, i int) error {
+	r, _ := utf8.DecodeRuneInString(input[i:])
+	return &encrypt.ErrInvalidRune{Rune: r, Pos: i}
+}
+
+// VIC осуществляет дешифрование упрощенного VIC в обратном порядке этапов
+func VIC(input string, key keys.VIC) (string, error) {
+	for i, r := range []rune(input) {
+		if r < '0' || r > '9' {
+			return "", &encrypt.ErrInvalidRune{Rune: r, Pos: i}
+		}
 	}
 
-	newK11 := (k22 * detInverse) % power
-	newK12 := ((-k12 + power) * detInverse) % power
-	newK21 := ((-k21 + power) * detInverse) % power
-	newK22 := (k11 * detInverse) % power
+	stream := input
+
+	if key.Transposition != "" {
+		stream = untranspose(stream, encrypt.KeywordRanks(key.Transposition, encrypt.VICDigitOrder))
+	}
+
+	if key.Seed != "" {
+		gamma := encrypt.ChainAddition(key.Seed, len(stream))
+		subtracted := []byte(stream)
+		for i := range subtracted {
+			subtracted[i] = byte('0' + (int(subtracted[i]-'0')-gamma[i]+10)%10)
+		}
+		stream = string(subtracted)
+	}
+
+	return Checkerboard(stream, key.Board)
+}
+
+// Vernam осуществляет дешифрование Вернама над алфавитом
+func Vernam(input, pad string, alphabetMap map[rune]int, power int) (string, error) {
+	if utf8.RuneCountInString(pad) < utf8.RuneCountInString(input) {
+		return "", fmt.Errorf("%w: pad of %d symbols is shorter than the text of %d", encrypt.ErrKeyLength, utf8.RuneCountInString(pad), utf8.RuneCountInString(input))
+	}
+	if err := encrypt.CheckText(input, alphabetMap); err != nil {
+		return "", err
+	}
+	if err := encrypt.CheckText(pad, alphabetMap); err != nil {
+		return "", fmt.Errorf("pad: %w", err)
+	}
 
 	reverseAlphabetMap := make(map[int]rune)
-	for char, idx := range alphabetMap {
-		reverseAlphabetMap[idx] = char
+	for char, index := range alphabetMap {
+		reverseAlphabetMap[index] = char
 	}
 
-	var ciphertext strings.Builder
-	text := []rune(input)
-	for i := 0; i < utf8.RuneCountInString(input); i += 2 {
-		p1 := alphabetMap[text[i]]
-		p2 := alphabetMap[text[i+1]]
+	padRunes := []rune(pad)
+	decryptedText := make([]rune, 0, len(padRunes))
+	for i, char := range []rune(input) {
+		idx := modmath.Mod(alphabetMap[char]-alphabetMap[padRunes[i]], power)
+		decryptedText = append(decryptedText, reverseAlphabetMap[idx])
 
-		ciphertext.WriteRune(reverseAlphabetMap[(p1*newK11+p2*newK21)%power])
-		ciphertext.WriteRune(reverseAlphabetMap[(p1*newK12+p2*newK22)%power])
+		if trace.Enabled() {
+			trace.Emit(trace.Step{Pos: i + 1, Input: string(char), Formula: "(idx-pad[i]) % power",
+				Values: fmt.Sprintf("(%d-%d) %% %d = %d", alphabetMap[char], alphabetMap[padRunes[i]], power, idx), Output: string(reverseAlphabetMap[idx])})
+		}
 	}
 
-	return ciphertext.String()
+	return string(decryptedText), nil
 }
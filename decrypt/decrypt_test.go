@@ -0,0 +1,144 @@
+package decrypt
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/marelinaa/cipher-algorithms/encrypt"
+	"github.com/marelinaa/cipher-algorithms/keys"
+	"github.com/marelinaa/cipher-algorithms/trace"
+	"github.com/marelinaa/cipher-algorithms/verify"
+)
+
+const alphabet = "АБВГДЕЁЖЗИЙКЛМНОПРСТУФХЦЧШЩЪЫЬЭЮЯ "
+
+func alphabetMap() map[rune]int {
+	m := make(map[rune]int)
+	for i, char := range []rune(alphabet) {
+		m[char] = i
+	}
+	return m
+}
+
+func TestAffineNotInvertible(t *testing.T) {
+	// 2 и 34 не взаимно просты
+	_, err := Affine("ПРИВЕТ", keys.Affine{K1: 2, K2: 5}, alphabetMap(), 34)
+	if !errors.Is(err, encrypt.ErrNotInvertible) {
+		t.Errorf("err = %v, want ErrNotInvertible", err)
+	}
+}
+
+func TestHillSingular(t *testing.T) {
+	tests := [][2][2]int{
+		{{1, 2}, {2, 4}}, // det = 0
+		{{2, 0}, {0, 1}}, // det = 2, не взаимно прост с 34
+		{{1, 1}, {0, 17}},
+	}
+	for _, key := range tests {
+		_, err := Hill("ПРИВЕТ", key, alphabetMap(), 34)
+		if !errors.Is(err, encrypt.ErrNotInvertible) {
+			t.Errorf("key %v: err = %v, want ErrNotInvertible", key, err)
+		}
+	}
+}
+
+func TestKeyLength(t *testing.T) {
+	if _, err := Vigenere("ПРИВЕТ", "", alphabetMap()); !errors.Is(err, encrypt.ErrKeyLength) {
+		t.Errorf("empty vigenere key: err = %v, want ErrKeyLength", err)
+	}
+	if _, err := Substitution("ПРИВЕТ", []rune("АБВ"), alphabetMap(), 34); !errors.Is(err, encrypt.ErrKeyLength) {
+		t.Errorf("short substitution key: err = %v, want ErrKeyLength", err)
+	}
+}
+
+func TestInvalidRunePosition(t *testing.T) {
+	// позиция считается в символах: перед 'Q' пять букв кириллицы, это десять байтов
+	_, err := Affine("ПРИВЕQТ", keys.Affine{K1: 3, K2: 5}, alphabetMap(), 34)
+	var invalid *encrypt.ErrInvalidRune
+	if !errors.As(err, &invalid) {
+		t.Fatalf("err = %v, want *ErrInvalidRune", err)
+	}
+	if invalid.Rune != 'Q' || invalid.Pos != 5 {
+		t.Errorf("invalid rune %q at %d, want 'Q' at 5", invalid.Rune, invalid.Pos)
+	}
+
+	_, err = Hill("ПРИВЕQ", [2][2]int{{3, 4}, {5, 9}}, alphabetMap(), 34)
+	if !errors.As(err, &invalid) || invalid.Pos != 5 {
+		t.Errorf("hill: err = %v, want 'Q' at 5", err)
+	}
+}
+
+func TestVICWithoutSeed(t *testing.T) {
+	// пустая гамма: только шахматная доска и перестановка
+	key, err := verify.VICKey("АСТРОНОМ|379||31452", alphabetMap(), 34)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key.Seed != "" || key.Transposition != "31452" {
+		t.Errorf("seed %q, transposition %q, want no seed and 31452", key.Seed, key.Transposition)
+	}
+
+	plaintext := "ШИФР БЕЗ ГАММЫ"
+	ciphertext, err := encrypt.VIC(plaintext, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := VIC(ciphertext, key); err != nil || got != plaintext {
+		t.Errorf("VIC(%q) = %q, %v, want %q", ciphertext, got, err, plaintext)
+	}
+}
+
+func TestTrace(t *testing.T) {
+	var steps int
+	trace.SetTracer(trace.TracerFunc(func(trace.Step) { steps++ }))
+	defer trace.SetTracer(nil)
+
+	plaintext := "ДИСК АЛЬБЕРТИ"
+	inner := []rune("ЯЮЭЬЫЪЩШЧЦХФУТСРПОНМЛКЙИЗЖЁЕДГВБА ")
+	tests := []struct {
+		name      string
+		encrypt   func(string) (string, error)
+		decrypt   func(string) (string, error)
+		wantSteps int // шаг на каждую букву, у Альберти еще и на каждый поворот диска
+	}{
+		{
+			"trithemius",
+			func(s string) (string, error) {
+				return encrypt.Trithemius(s, keys.Trithemius{Shift: -3, Step: 5}, alphabetMap(), 34)
+			},
+			func(s string) (string, error) {
+				return Trithemius(s, keys.Trithemius{Shift: -3, Step: 5}, alphabetMap(), 34)
+			},
+			13,
+		},
+		{
+			"alberti",
+			func(s string) (string, error) {
+				return encrypt.Alberti(s, keys.Alberti{Inner: inner, Index: 'К', Period: 4}, alphabetMap(), 34)
+			},
+			func(s string) (string, error) {
+				return Alberti(s, keys.Alberti{Inner: inner, Index: 'К', Period: 4}, alphabetMap(), 34)
+			},
+			13 + 4,
+		},
+	}
+	for _, tt := range tests {
+		steps = 0
+		ciphertext, err := tt.encrypt(plaintext)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if steps != tt.wantSteps {
+			t.Errorf("%s: encryption traced %d steps, want %d", tt.name, steps, tt.wantSteps)
+		}
+
+		steps = 0
+		got, err := tt.decrypt(ciphertext)
+		if err != nil || got != plaintext {
+			t.Errorf("%s: decrypt(%q) = %q, %v, want %q", tt.name, ciphertext, got, err, plaintext)
+		}
+		if steps != tt.wantSteps {
+			t.Errorf("%s: decryption traced %d steps, want %d", tt.name, steps, tt.wantSteps)
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package decrypt
+
+import (
+	"crypto/cipher"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/marelinaa/cipher-algorithms/encrypt"
+	"github.com/marelinaa/cipher-algorithms/modmath"
+)
+
+// Unpad убирает дополнение процедуры 2 ГОСТ Р 34.13-2015
+func Unpad(data []byte, blockSize int) ([]byte, error) {
+	if len(data) == 0 || len(data)%blockSize != 0 {
+		return nil, fmt.Errorf("data length must be a positive multiple of the block size %d", blockSize)
+	}
+
+	for i := len(data) - 1; i >= len(data)-blockSize; i-- {
+		switch data[i] {
+		case 0:
+			continue
+		case 0x80:
+			return data[:i], nil
+		}
+		break
+	}
+
+	return nil, errors.New("invalid padding")
+}
+
+func xorBytes(dst, a, b []byte) {
+	for i := range dst {
+		dst[i] = a[i] ^ b[i]
+	}
+}
+
+// ECB осуществляет дешифрование в режиме простой замены
+func ECB(b cipher.Block, ciphertext []byte) ([]byte, error) {
+	size := b.BlockSize()
+	if len(ciphertext)%size != 0 {
+		return nil, fmt.Errorf("ciphertext length must be a multiple of the block size %d", size)
+	}
+
+	plaintext := make([]byte, len(ciphertext))
+	for i := 0; i < len(ciphertext); i += size {
+		b.Decrypt(plaintext[i:i+size], ciphertext[i:i+size])
+	}
+	return Unpad(plaintext, size)
+}
+
+// CBC осуществляет дешифрование в режиме сцепления блоков: P_i = D(C_i) xor C_{i-1}
+func CBC(b cipher.Block, iv, ciphertext []byte) ([]byte, error) {
+	size := b.BlockSize()
+	if len(ciphertext)%size != 0 {
+		return nil, fmt.Errorf("ciphertext length must be a multiple of the block size %d", size)
+	}
+
+	plaintext := make([]byte, len(ciphertext))
+	prev := iv
+	for i := 0; i < len(ciphertext); i += size {
+		b.Decrypt(plaintext[i:i+size], ciphertext[i:i+size])
+		xorBytes(plaintext[i:i+size], plaintext[i:i+size], prev)
+		prev = ciphertext[i : i+size]
+	}
+	return Unpad(plaintext, size)
+}
+
+// CFB осуществляет дешифрование в режиме обратной связи по шифротексту: P_i = C_i xor E(C_{i-1})
+func CFB(b cipher.Block, iv, ciphertext []byte) []byte {
+	size := b.BlockSize()
+	plaintext := make([]byte, len(ciphertext))
+	gamma := make([]byte, size)
+	prev := iv
+	for i := 0; i < len(ciphertext); i += size {
+		end := min(i+size, len(ciphertext))
+		b.Encrypt(gamma, prev)
+		xorBytes(plaintext[i:end], ciphertext[i:end], gamma)
+		prev = ciphertext[i:end]
+	}
+	return plaintext
+}
+
+// BlockMode decrypts the ciphertext produced by encrypt.BlockMode, the IV is read from its beginning
+func BlockMode(b cipher.Block, mode string, ciphertext []byte) ([]byte, error) {
+	ivSize := encrypt.IVSize(mode, b.BlockSize())
+	if len(ciphertext) < ivSize {
+		return nil, fmt.Errorf("ciphertext is shorter than the IV")
+	}
+	iv, ciphertext := ciphertext[:ivSize], ciphertext[ivSize:]
+
+	switch mode {
+	case encrypt.ModeECB:
+		return ECB(b, ciphertext)
+	case encrypt.ModeCBC:
+		return CBC(b, iv, ciphertext)
+	case encrypt.ModeCFB:
+		return CFB(b, iv, ciphertext), nil
+	case encrypt.ModeOFB:
+		return encrypt.OFB(b, iv, ciphertext), nil
+	case encrypt.ModeCTR:
+		return encrypt.CTR(b, iv, ciphertext), nil
+	}
+
+	return nil, fmt.Errorf("unknown mode of operation: %s", mode)
+}
+
+func subtractIndices(dst, a, b []int, power int) {
+	for i := range dst {
+		dst[i] = modmath.Mod(a[i]-b[i], power)
+	}
+}
+
+// IndexMode decrypts alphabet indices produced by encrypt.IndexMode, the IV is read from their beginning.
+// Padding symbols of ECB and CBC stay in the text, as with decrypt.Hill
+func IndexMode(c encrypt.IndexCipher, mode string, ciphertext []int) ([]int, error) {
+	size, power := c.BlockSize(), c.Power()
+
+	ivSize := size
+	if mode == encrypt.ModeECB {
+		ivSize = 0
+	}
+	if len(ciphertext) < ivSize {
+		return nil, fmt.Errorf("ciphertext is shorter than the IV")
+	}
+	prev := append([]int{}, ciphertext[:ivSize]...)
+	ciphertext = ciphertext[ivSize:]
+
+	if (mode == encrypt.ModeECB || mode == encrypt.ModeCBC) && len(ciphertext)%size != 0 {
+		return nil, fmt.Errorf("ciphertext length must be a multiple of the block size %d", size)
+	}
+
+	plaintext := make([]int, len(ciphertext))
+	gamma := make([]int, size)
+	for i := 0; i < len(ciphertext); i += size {
+		end := min(i+size, len(ciphertext))
+		block := plaintext[i:end]
+
+		switch mode {
+		case encrypt.ModeECB:
+			c.Decrypt(block, ciphertext[i:end])
+		case encrypt.ModeCBC:
+			c.Decrypt(block, ciphertext[i:end])
+			subtractIndices(block, block, prev, power)
+			prev = ciphertext[i:end]
+		case encrypt.ModeCFB:
+			c.Encrypt(gamma, prev)
+			subtractIndices(block, ciphertext[i:end], gamma, power)
+			prev = ciphertext[i:end]
+		case encrypt.ModeOFB:
+			c.Encrypt(prev, prev)
+			subtractIndices(block, ciphertext[i:end], prev, power)
+		case encrypt.ModeCTR:
+			c.Encrypt(gamma, prev)
+			subtractIndices(block, ciphertext[i:end], gamma, power)
+			encrypt.IncrementIndices(prev, power)
+		default:
+			return nil, fmt.Errorf("unknown mode of operation: %s", mode)
+		}
+	}
+
+	return plaintext, nil
+}
+
+// HillMode осуществляет дешифрование Хилла в заданном режиме сцепления
+func HillMode(input string, key [2][2]int, mode string, alphabetMap map[rune]int, power int) (string, error) {
+	c, err := encrypt.NewHillCipher(key, power)
+	if err != nil {
+		return "", err
+	}
+	if err := encrypt.CheckText(input, alphabetMap); err != nil {
+		return "", err
+	}
+
+	ciphertext := make([]int, 0, utf8.RuneCountInString(input))
+	for _, char := range input {
+		ciphertext = append(ciphertext, alphabetMap[char])
+	}
+
+	plaintext, err := IndexMode(c, mode, ciphertext)
+	if err != nil {
+		return "", err
+	}
+
+	reverseAlphabetMap := make(map[int]rune)
+	for char, idx := range alphabetMap {
+		reverseAlphabetMap[idx] = char
+	}
+
+	var plainText strings.Builder
+	for _, idx := range plaintext {
+		plainText.WriteRune(reverseAlphabetMap[idx])
+	}
+
+	return plainText.String(), nil
+}
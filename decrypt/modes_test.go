@@ -0,0 +1,119 @@
+package decrypt
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/marelinaa/cipher-algorithms/encrypt"
+	"github.com/marelinaa/cipher-algorithms/gost"
+)
+
+func TestHillMode(t *testing.T) {
+	key := [2][2]int{{3, 4}, {5, 9}} // ГДЕИ
+	plaintext := "ШИФР ХИЛЛА"
+
+	// без сцепления режим совпадает с обычным шифром Хилла
+	hill, err := encrypt.Hill(plaintext, key, alphabetMap(), 34)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecb, err := encrypt.HillMode(plaintext, key, encrypt.ModeECB, alphabetMap(), 34)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ecb != hill {
+		t.Errorf("ecb = %q, want %q as encrypt.Hill", ecb, hill)
+	}
+
+	for _, mode := range []string{encrypt.ModeECB, encrypt.ModeCBC, encrypt.ModeCTR} {
+		ciphertext, err := encrypt.HillMode(plaintext, key, mode, alphabetMap(), 34)
+		if err != nil {
+			t.Fatalf("%s: %v", mode, err)
+		}
+
+		ivSize := 2
+		if mode == encrypt.ModeECB {
+			ivSize = 0
+		}
+		if n := utf8.RuneCountInString(ciphertext); n != utf8.RuneCountInString(plaintext)+ivSize {
+			t.Errorf("%s: ciphertext has %d symbols, want the text and an IV of %d", mode, n, ivSize)
+		}
+
+		got, err := HillMode(ciphertext, key, mode, alphabetMap(), 34)
+		if err != nil || got != plaintext {
+			t.Errorf("%s: HillMode(%q) = %q, %v, want %q", mode, ciphertext, got, err, plaintext)
+		}
+	}
+}
+
+func decodeHex(t *testing.T, s string) []byte {
+	t.Helper()
+	data, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// примеры из приложения А.2 ГОСТ Р 34.13-2015 для Магмы
+var (
+	magmaKey       = "ffeeddccbbaa99887766554433221100f0f1f2f3f4f5f6f7f8f9fafbfcfdfeff"
+	magmaPlaintext = []string{"92def06b3c130a59", "db54c704f8189d20", "4a98fb2e67a8024c", "8912409b17b57e41"}
+)
+
+func TestMagmaCTR(t *testing.T) {
+	block, err := gost.NewMagma(decodeHex(t, magmaKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	iv := decodeHex(t, "12345678")
+	plaintext := decodeHex(t, magmaPlaintext[0]+magmaPlaintext[1]+magmaPlaintext[2]+magmaPlaintext[3])
+	want := decodeHex(t, "4e98110c97b7b93c"+"3e250d93d6e85d69"+"136d868807b2dbef"+"568eb680ab52a12d")
+
+	if got := encrypt.CTR(block, iv, plaintext); !bytes.Equal(got, want) {
+		t.Errorf("CTR = %x, want %x", got, want)
+	}
+	if got := encrypt.CTR(block, iv, want); !bytes.Equal(got, plaintext) {
+		t.Errorf("CTR of the ciphertext = %x, want %x", got, plaintext)
+	}
+}
+
+func TestMagmaCBC(t *testing.T) {
+	block, err := gost.NewMagma(decodeHex(t, magmaKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"96d1b05eea683919", "aff76129abb937b9", "5058b4a1c4bc0019", "20b78b1a7cd7e667"}
+
+	// в стандарте регистр вектора инициализации занимает три блока, поэтому
+	// шифротекст распадается на три цепочки: блоки 1 и 4, блок 2 и блок 3
+	chains := []struct {
+		iv     string
+		blocks []int
+	}{
+		{"1234567890abcdef", []int{0, 3}},
+		{"234567890abcdef1", []int{1}},
+		{"34567890abcdef12", []int{2}},
+	}
+	for _, chain := range chains {
+		var plaintext, ciphertext []byte
+		for _, i := range chain.blocks {
+			plaintext = append(plaintext, decodeHex(t, magmaPlaintext[i])...)
+			ciphertext = append(ciphertext, decodeHex(t, want[i])...)
+		}
+		iv := decodeHex(t, chain.iv)
+
+		// encrypt.CBC добавляет блок дополнения, он не входит в пример
+		got := encrypt.CBC(block, iv, plaintext)
+		if !bytes.Equal(got[:len(ciphertext)], ciphertext) {
+			t.Errorf("IV %s: CBC = %x, want %x", chain.iv, got[:len(ciphertext)], ciphertext)
+		}
+
+		decrypted, err := CBC(block, iv, got)
+		if err != nil || !bytes.Equal(decrypted, plaintext) {
+			t.Errorf("IV %s: decrypt CBC = %x, %v, want %x", chain.iv, decrypted, err, plaintext)
+		}
+	}
+}
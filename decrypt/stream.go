@@ -0,0 +1,40 @@
+package decrypt
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/marelinaa/cipher-algorithms/encrypt"
+	"github.com/marelinaa/cipher-algorithms/modmath"
+	"github.com/marelinaa/cipher-algorithms/stream"
+	"github.com/marelinaa/cipher-algorithms/trace"
+)
+
+// Stream осуществляет дешифрование Вернама над алфавитом с гаммой от генератора
+func Stream(input string, g stream.Generator, alphabetMap map[rune]int, power int) (string, error) {
+	if err := encrypt.CheckText(input, alphabetMap); err != nil {
+		return "", err
+	}
+
+	reverseAlphabetMap := make(map[int]rune)
+	for char, index := range alphabetMap {
+		reverseAlphabetMap[index] = char
+	}
+
+	var plainText strings.Builder
+	for i, char := range []rune(input) {
+		gamma, err := stream.Index(g, power)
+		if err != nil {
+			return "", err
+		}
+		idx := modmath.Mod(alphabetMap[char]-gamma, power)
+		plainText.WriteRune(reverseAlphabetMap[idx])
+
+		if trace.Enabled() {
+			trace.Emit(trace.Step{Pos: i + 1, Input: string(char), Formula: "(idx-gamma) % power",
+				Values: fmt.Sprintf("(%d-%d) %% %d = %d", alphabetMap[char], gamma, power, idx), Output: string(reverseAlphabetMap[idx])})
+		}
+	}
+
+	return plainText.String(), nil
+}
@@ -0,0 +1,85 @@
+package ecc
+
+import "math/big"
+
+func number(s string) *big.Int {
+	n, ok := new(big.Int).SetString(s, 0)
+	if !ok {
+		panic("ecc: bad curve constant " + s)
+	}
+	return n
+}
+
+// Curves are the available curves: small ones from textbooks that can be checked by hand,
+// the example curve of GOST R 34.10-2012 and the CryptoPro and TC26 parameter sets
+var Curves = []*Curve{
+	{
+		Name: "e11",
+		P:    number("11"), A: number("1"), B: number("6"),
+		X: number("2"), Y: number("7"),
+		Q: number("13"),
+	},
+	{
+		Name: "e17",
+		P:    number("17"), A: number("2"), B: number("2"),
+		X: number("5"), Y: number("1"),
+		Q: number("19"),
+	},
+	{
+		Name: "e211",
+		P:    number("211"), A: number("0"), B: number("207"),
+		X: number("2"), Y: number("2"),
+		Q: number("241"),
+	},
+	{
+		// контрольный пример из приложения А.1 ГОСТ Р 34.10-2012
+		Name: "gost-test-256",
+		P:    number("0x8000000000000000000000000000000000000000000000000000000000000431"),
+		A:    number("7"),
+		B:    number("0x5FBFF498AA938CE739B8E022FBAFEF40563F6E6A3472FC2A514C0CE9DAE23B7E"),
+		X:    number("2"),
+		Y:    number("0x08E2A8A0E65147D4BD6316030E16D19C85C97F0A9CA267122B96ABBCEA7E8FC8"),
+		Q:    number("0x8000000000000000000000000000000150FE8A1892976154C59CFC193ACCF5B3"),
+	},
+	{
+		Name: "cryptopro-a",
+		P:    number("0xFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFD97"),
+		A:    number("0xFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFD94"),
+		B:    number("0xA6"),
+		X:    number("1"),
+		Y:    number("0x8D91E471E0989CDA27DF505A453F2B7635294F2DDF23E3B122ACC99C9E9F1E14"),
+		Q:    number("0xFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFF6C611070995AD10045841B09B761B893"),
+	},
+	{
+		Name: "cryptopro-b",
+		P:    number("0x8000000000000000000000000000000000000000000000000000000000000C99"),
+		A:    number("0x8000000000000000000000000000000000000000000000000000000000000C96"),
+		B:    number("0x3E1AF419A269A5F866A7D3C25C3DF80AE979259373FF2B182F49D4CE7E1BBC8B"),
+		X:    number("1"),
+		Y:    number("0x3FA8124359F96680B83D1C3EB2C070E5C545C9858D03ECFB744BF8D717717EFC"),
+		Q:    number("0x800000000000000000000000000000015F700CFFF1A624E5E497161BCC8A198F"),
+	},
+	{
+		Name: "cryptopro-c",
+		P:    number("0x9B9F605F5A858107AB1EC85E6B41C8AACF846E86789051D37998F7B9022D759B"),
+		A:    number("0x9B9F605F5A858107AB1EC85E6B41C8AACF846E86789051D37998F7B9022D7598"),
+		B:    number("0x805A"),
+		X:    number("0"),
+		Y:    number("0x41ECE55743711A8C3CBF3783CD08C0EE4D4DC440D4641A8F366E550DFDB3BB67"),
+		Q:    number("0x9B9F605F5A858107AB1EC85E6B41C8AA582CA3511EDDFB74F02F3A6598980BB9"),
+	},
+	{
+		Name: "tc26-512-a",
+		P: number("0xFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFF" +
+			"FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFDC7"),
+		A: number("0xFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFF" +
+			"FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFDC4"),
+		B: number("0xE8C2505DEDFC86DDC1BD0B2B6667F1DA34B82574761CB0E879BD081CFD0B6265" +
+			"EE3CB090F30D27614CB4574010DA90DD862EF9D4EBEE4761503190785A71C760"),
+		X: number("3"),
+		Y: number("0x7503CFE87A836AE3A61B8816E25450E6CE5E1C93ACF1ABC1778064FDCBEFA921" +
+			"DF1626BE4FD036E93D75E6A50E3A41E98028FE5FC235F5B889A589CB5215F2A4"),
+		Q: number("0xFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFF" +
+			"27E69532F48D89116FF22B8D4E0560609B4B38ABFAD2B85DCACDB1411F10B275"),
+	},
+}
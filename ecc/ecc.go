@@ -0,0 +1,246 @@
+package ecc
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// Curve is the curve y^2 = x^3 + ax + b over the prime field P with the base point (X, Y) of the prime order Q
+type Curve struct {
+	Name    string
+	P, A, B *big.Int
+	X, Y    *big.Int
+	Q       *big.Int
+}
+
+// Point is a point of the curve in affine coordinates, the point at infinity has nil coordinates
+type Point struct {
+	X, Y *big.Int
+}
+
+// Infinity is the neutral element of the group
+var Infinity = Point{}
+
+// IsInfinity reports whether the point is the point at infinity
+func (pt Point) IsInfinity() bool {
+	return pt.X == nil
+}
+
+func (pt Point) String() string {
+	if pt.IsInfinity() {
+		return "O"
+	}
+	return fmt.Sprintf("(%v, %v)", pt.X, pt.Y)
+}
+
+// Equal reports whether the points are the same
+func (pt Point) Equal(other Point) bool {
+	if pt.IsInfinity() || other.IsInfinity() {
+		return pt.IsInfinity() == other.IsInfinity()
+	}
+	return pt.X.Cmp(other.X) == 0 && pt.Y.Cmp(other.Y) == 0
+}
+
+// Base returns the base point G
+func (c *Curve) Base() Point {
+	return Point{X: c.X, Y: c.Y}
+}
+
+// IsOnCurve checks that the point satisfies the curve equation
+func (c *Curve) IsOnCurve(pt Point) bool {
+	if pt.IsInfinity() {
+		return true
+	}
+	if pt.X.Sign() < 0 || pt.X.Cmp(c.P) >= 0 || pt.Y.Sign() < 0 || pt.Y.Cmp(c.P) >= 0 {
+		return false
+	}
+
+	left := new(big.Int).Mul(pt.Y, pt.Y)
+	left.Mod(left, c.P)
+	return left.Cmp(c.rhs(pt.X)) == 0
+}
+
+// rhs computes x^3 + ax + b mod p
+func (c *Curve) rhs(x *big.Int) *big.Int {
+	r := new(big.Int).Mul(x, x)
+	r.Add(r, c.A)
+	r.Mul(r, x)
+	r.Add(r, c.B)
+	return r.Mod(r, c.P)
+}
+
+// Neg returns -P = (x, -y)
+func (c *Curve) Neg(pt Point) Point {
+	if pt.IsInfinity() {
+		return pt
+	}
+	return Point{X: new(big.Int).Set(pt.X), Y: new(big.Int).Mod(new(big.Int).Neg(pt.Y), c.P)}
+}
+
+// Add adds two points by the chord rule
+func (c *Curve) Add(p1, p2 Point) Point {
+	if p1.IsInfinity() {
+		return p2
+	}
+	if p2.IsInfinity() {
+		return p1
+	}
+	if p1.X.Cmp(p2.X) == 0 {
+		// P + (-P) = O, P + P считается по правилу касательной
+		if new(big.Int).Add(p1.Y, p2.Y).Cmp(c.P) == 0 || (p1.Y.Sign() == 0 && p2.Y.Sign() == 0) {
+			return Infinity
+		}
+		return c.Double(p1)
+	}
+
+	// lambda = (y2 - y1) / (x2 - x1)
+	num := new(big.Int).Sub(p2.Y, p1.Y)
+	den := new(big.Int).Sub(p2.X, p1.X)
+	den.Mod(den, c.P)
+	lambda := num.Mul(num, den.ModInverse(den, c.P))
+	lambda.Mod(lambda, c.P)
+
+	return c.fromSlope(lambda, p1, p2.X)
+}
+
+// Double doubles the point by the tangent rule
+func (c *Curve) Double(pt Point) Point {
+	if pt.IsInfinity() || pt.Y.Sign() == 0 {
+		return Infinity
+	}
+
+	// lambda = (3x^2 + a) / 2y
+	num := new(big.Int).Mul(pt.X, pt.X)
+	num.Mul(num, big.NewInt(3))
+	num.Add(num, c.A)
+	den := new(big.Int).Lsh(pt.Y, 1)
+	den.Mod(den, c.P)
+	lambda := num.Mul(num, den.ModInverse(den, c.P))
+	lambda.Mod(lambda, c.P)
+
+	return c.fromSlope(lambda, pt, pt.X)
+}
+
+// fromSlope finishes the addition: x3 = lambda^2 - x1 - x2, y3 = lambda(x1 - x3) - y1
+func (c *Curve) fromSlope(lambda *big.Int, p1 Point, x2 *big.Int) Point {
+	x3 := new(big.Int).Mul(lambda, lambda)
+	x3.Sub(x3, p1.X)
+	x3.Sub(x3, x2)
+	x3.Mod(x3, c.P)
+
+	y3 := new(big.Int).Sub(p1.X, x3)
+	y3.Mul(y3, lambda)
+	y3.Sub(y3, p1.Y)
+	y3.Mod(y3, c.P)
+
+	return Point{X: x3, Y: y3}
+}
+
+// ScalarMult computes kP by doubling and adding from the most significant bit of k
+func (c *Curve) ScalarMult(pt Point, k *big.Int) Point {
+	if k.Sign() < 0 {
+		return c.ScalarMult(c.Neg(pt), new(big.Int).Neg(k))
+	}
+
+	result := Infinity
+	for i := k.BitLen() - 1; i >= 0; i-- {
+		result = c.Double(result)
+		if k.Bit(i) == 1 {
+			result = c.Add(result, pt)
+		}
+	}
+	return result
+}
+
+// ScalarBaseMult computes kG
+func (c *Curve) ScalarBaseMult(k *big.Int) Point {
+	return c.ScalarMult(c.Base(), k)
+}
+
+// MaxEnumerate is the largest field for which Points lists every point
+const MaxEnumerate = 1 << 16
+
+// Points lists all points of a small curve starting with O, so students can check the group by hand
+func (c *Curve) Points() ([]Point, error) {
+	if c.P.Cmp(big.NewInt(MaxEnumerate)) > 0 {
+		return nil, fmt.Errorf("curve %s is too large to list its points", c.Name)
+	}
+
+	points := []Point{Infinity}
+	for x := int64(0); x < c.P.Int64(); x++ {
+		bx := big.NewInt(x)
+		r := c.rhs(bx)
+		y := new(big.Int).ModSqrt(r, c.P)
+		if y == nil {
+			continue
+		}
+
+		points = append(points, Point{X: bx, Y: y})
+		if y.Sign() != 0 {
+			points = append(points, Point{X: bx, Y: new(big.Int).Sub(c.P, y)})
+		}
+	}
+	return points, nil
+}
+
+// Validate checks the curve parameters: a nonsingular curve, G on it and qG = O
+func (c *Curve) Validate() error {
+	// 4a^3 + 27b^2 != 0 mod p
+	d := new(big.Int).Exp(c.A, big.NewInt(3), c.P)
+	d.Mul(d, big.NewInt(4))
+	d.Add(d, new(big.Int).Mul(big.NewInt(27), new(big.Int).Mul(c.B, c.B)))
+	if d.Mod(d, c.P).Sign() == 0 {
+		return fmt.Errorf("curve %s is singular", c.Name)
+	}
+
+	if !c.IsOnCurve(c.Base()) {
+		return fmt.Errorf("base point of %s is not on the curve", c.Name)
+	}
+	if !c.ScalarBaseMult(c.Q).IsInfinity() {
+		return fmt.Errorf("base point of %s does not have order q", c.Name)
+	}
+	return nil
+}
+
+// ParsePoint reads a point written as x,y in decimal or with the 0x prefix, O is the point at infinity
+// and G is the base point
+func (c *Curve) ParsePoint(s string) (Point, error) {
+	switch strings.TrimSpace(s) {
+	case "O", "o":
+		return Infinity, nil
+	case "G", "g":
+		return c.Base(), nil
+	}
+
+	xs, ys, found := strings.Cut(s, ",")
+	if !found {
+		return Point{}, fmt.Errorf("point must look like x,y, O or G: %q", s)
+	}
+	x, okX := new(big.Int).SetString(strings.TrimSpace(xs), 0)
+	y, okY := new(big.Int).SetString(strings.TrimSpace(ys), 0)
+	if !okX || !okY {
+		return Point{}, fmt.Errorf("point coordinates must be numbers: %q", s)
+	}
+
+	pt := Point{X: x, Y: y}
+	if !c.IsOnCurve(pt) {
+		return Point{}, fmt.Errorf("point %v is not on the curve %s", pt, c.Name)
+	}
+	return pt, nil
+}
+
+// Lookup finds the curve by its name
+func Lookup(name string) (*Curve, error) {
+	for _, c := range Curves {
+		if c.Name == name {
+			return c, nil
+		}
+	}
+
+	names := make([]string, len(Curves))
+	for i, c := range Curves {
+		names[i] = c.Name
+	}
+	return nil, fmt.Errorf("unknown curve %q, choose one of %s", name, strings.Join(names, ", "))
+}
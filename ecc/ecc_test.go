@@ -0,0 +1,83 @@
+package ecc
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestCurvesValidate(t *testing.T) {
+	for _, c := range Curves {
+		if err := c.Validate(); err != nil {
+			t.Errorf("%s: %v", c.Name, err)
+		}
+	}
+}
+
+// контрольный пример из приложения А.1 ГОСТ Р 34.10-2012
+func TestGOSTExample(t *testing.T) {
+	c, err := Lookup("gost-test-256")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := number("55441196065363246126355624130324183196576709222340016572108097750006097525544")
+	e := number("20798893674476452017134061561508270130637142515379653289952617252661468872421")
+	k := number("53854137677348463731403841147996619241504003434302020712960838528893196233395")
+	wantR := number("29700980915817952874371204983938256990422752107994319651632687982059210933395")
+	wantS := number("574973400270084654178925310019147038455227042649098563933718999175515839552")
+	public := Point{
+		X: number("57520216126176808443631405023338071176630104906313632182896741342206604859403"),
+		Y: number("17614944419213781543809391949654080031942662045363639260709847859438286763994"),
+	}
+
+	priv, err := NewPrivateKey(c, d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !priv.Public.Equal(public) {
+		t.Errorf("public key = %v, want %v", priv.Public, public)
+	}
+
+	sig, err := SignHash(priv, e, k)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sig.R.Cmp(wantR) != 0 || sig.S.Cmp(wantS) != 0 {
+		t.Errorf("signature = (%x, %x), want (%x, %x)", sig.R, sig.S, wantR, wantS)
+	}
+
+	v, err := VerifyHash(c, public, e, wantR, wantS)
+	if err != nil {
+		t.Fatalf("VerifyHash: %v", err)
+	}
+	if v.R.Cmp(wantR) != 0 {
+		t.Errorf("R = %x, want %x", v.R, wantR)
+	}
+
+	// подпись другого хеша не проходит проверку
+	other := new(big.Int).Add(e, big.NewInt(1))
+	if _, err := VerifyHash(c, public, other, wantR, wantS); err == nil {
+		t.Error("VerifyHash accepted the signature for another hash")
+	}
+}
+
+func TestSignVerify(t *testing.T) {
+	for _, name := range []string{"e211", "cryptopro-a", "tc26-512-a"} {
+		c, err := Lookup(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		priv, err := GenerateKey(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		message := []byte("message")
+		sig, err := Sign(priv, message)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Verify(c, priv.Public, message, sig.R, sig.S); err != nil {
+			t.Errorf("%s: Verify: %v", name, err)
+		}
+	}
+}
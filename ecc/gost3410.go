@@ -0,0 +1,159 @@
+package ecc
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+
+	"github.com/marelinaa/cipher-algorithms/gost"
+)
+
+// PrivateKey is the GOST R 34.10-2012 key: the secret d and the public point Q = dG
+type PrivateKey struct {
+	Curve  *Curve
+	D      *big.Int
+	Public Point
+}
+
+// GenerateKey generates a key with a random d in [1, q-1]
+func GenerateKey(c *Curve) (*PrivateKey, error) {
+	d, err := randomScalar(c)
+	if err != nil {
+		return nil, err
+	}
+	return NewPrivateKey(c, d)
+}
+
+// NewPrivateKey creates the key with the given secret d
+func NewPrivateKey(c *Curve, d *big.Int) (*PrivateKey, error) {
+	if d.Sign() <= 0 || d.Cmp(c.Q) >= 0 {
+		return nil, errors.New("secret key must be between 1 and q-1")
+	}
+	return &PrivateKey{Curve: c, D: d, Public: c.ScalarBaseMult(d)}, nil
+}
+
+func randomScalar(c *Curve) (*big.Int, error) {
+	for {
+		k, err := rand.Int(rand.Reader, c.Q)
+		if err != nil {
+			return nil, err
+		}
+		if k.Sign() != 0 {
+			return k, nil
+		}
+	}
+}
+
+// Digest hashes the message with Streebog (512 bits for curves with q above 2^256, 256 bits otherwise)
+// and returns e = alpha mod q, where alpha is the number whose binary representation is the hash vector.
+// The digest bytes go from the least significant, as Streebog produces them
+func Digest(c *Curve, message []byte) *big.Int {
+	var h []byte
+	if c.Q.BitLen() > 256 {
+		h = gost.Streebog512(message)
+	} else {
+		h = gost.Streebog256(message)
+	}
+	slices.Reverse(h)
+
+	e := new(big.Int).SetBytes(h)
+	e.Mod(e, c.Q)
+	// при e = 0 стандарт предписывает взять e = 1
+	if e.Sign() == 0 {
+		e.SetInt64(1)
+	}
+	return e
+}
+
+// Signature holds the signature (r, s) and the values used to make it
+type Signature struct {
+	E *big.Int // hash of the message reduced modulo q
+	K *big.Int // random k in [1, q-1]
+	C Point    // C = kG
+	R *big.Int // r = x_C mod q
+	S *big.Int // s = (rd + ke) mod q
+}
+
+// Sign signs the message with a random k
+func Sign(priv *PrivateKey, message []byte) (*Signature, error) {
+	e := Digest(priv.Curve, message)
+	for {
+		k, err := randomScalar(priv.Curve)
+		if err != nil {
+			return nil, err
+		}
+
+		sig, err := SignHash(priv, e, k)
+		if err == nil {
+			return sig, nil
+		}
+	}
+}
+
+// SignHash signs the hash e with the given k, it fails when r or s is zero and another k is needed.
+// With the k of the standard's example it reproduces its signature
+func SignHash(priv *PrivateKey, e, k *big.Int) (*Signature, error) {
+	c := priv.Curve
+	sig := &Signature{E: e, K: k, C: c.ScalarBaseMult(k)}
+	if sig.C.IsInfinity() {
+		return nil, errors.New("kG is the point at infinity, choose another k")
+	}
+
+	sig.R = new(big.Int).Mod(sig.C.X, c.Q)
+	if sig.R.Sign() == 0 {
+		return nil, errors.New("r is zero, choose another k")
+	}
+
+	sig.S = new(big.Int).Mul(sig.R, priv.D)
+	sig.S.Add(sig.S, new(big.Int).Mul(k, e))
+	sig.S.Mod(sig.S, c.Q)
+	if sig.S.Sign() == 0 {
+		return nil, errors.New("s is zero, choose another k")
+	}
+
+	return sig, nil
+}
+
+// Verification holds the values computed while checking a signature
+type Verification struct {
+	E  *big.Int // hash of the message reduced modulo q
+	V  *big.Int // v = e^-1 mod q
+	Z1 *big.Int // z1 = sv mod q
+	Z2 *big.Int // z2 = -rv mod q
+	C  Point    // C = z1*G + z2*Q
+	R  *big.Int // R = x_C mod q, must be equal to r
+}
+
+// Verify checks the signature (r, s) of the message with the public point
+func Verify(c *Curve, public Point, message []byte, r, s *big.Int) (*Verification, error) {
+	return VerifyHash(c, public, Digest(c, message), r, s)
+}
+
+// VerifyHash checks the signature (r, s) of the hash e, the returned values are filled even when it is not valid
+func VerifyHash(c *Curve, public Point, e, r, s *big.Int) (*Verification, error) {
+	if r.Sign() <= 0 || r.Cmp(c.Q) >= 0 || s.Sign() <= 0 || s.Cmp(c.Q) >= 0 {
+		return nil, errors.New("signature is out of range")
+	}
+	if public.IsInfinity() || !c.IsOnCurve(public) {
+		return nil, fmt.Errorf("public key %v is not a point of the curve %s", public, c.Name)
+	}
+
+	v := &Verification{E: e, V: new(big.Int).ModInverse(e, c.Q)}
+	v.Z1 = new(big.Int).Mul(s, v.V)
+	v.Z1.Mod(v.Z1, c.Q)
+	v.Z2 = new(big.Int).Mul(r, v.V)
+	v.Z2.Neg(v.Z2).Mod(v.Z2, c.Q)
+
+	v.C = c.Add(c.ScalarBaseMult(v.Z1), c.ScalarMult(public, v.Z2))
+	if v.C.IsInfinity() {
+		return v, errors.New("signature is not valid")
+	}
+	v.R = new(big.Int).Mod(v.C.X, c.Q)
+
+	if v.R.Cmp(r) != 0 {
+		return v, errors.New("signature is not valid")
+	}
+	return v, nil
+}
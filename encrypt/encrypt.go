@@ -1,17 +1,25 @@
 package encrypt
 
 import (
-	"errors"
 	"fmt"
 	"sort"
+	"strconv"
 	"strings"
 	"unicode/utf8"
 
+	"github.com/marelinaa/cipher-algorithms/enigma"
+	"github.com/marelinaa/cipher-algorithms/keygen"
 	"github.com/marelinaa/cipher-algorithms/keys"
+	"github.com/marelinaa/cipher-algorithms/modmath"
+	"github.com/marelinaa/cipher-algorithms/trace"
 	"golang.org/x/exp/rand"
 )
 
-func Caesar(input string, key int, alphabetMap map[rune]int, power int) string {
+func Caesar(input string, key int, alphabetMap map[rune]int, power int) (string, error) {
+	if err := CheckText(input, alphabetMap); err != nil {
+		return "", err
+	}
+
 	reverseAlphabetMap := make(map[int]rune)
 
 	for char, index := range alphabetMap {
@@ -20,19 +28,29 @@ func Caesar(input string, key int, alphabetMap map[rune]int, power int) string {
 
 	var encryptedText []rune
 
-	for _, char := range input {
+	for i, char := range []rune(input) {
 		idx := alphabetMap[char]
-		newIdx := (idx + key) % power
-		if newIdx < 0 {
-			newIdx += power
-		}
+		newIdx := modmath.Mod(idx+key, power)
 		encryptedText = append(encryptedText, reverseAlphabetMap[newIdx])
+
+		if trace.Enabled() {
+			trace.Emit(trace.Step{Pos: i + 1, Input: string(char), Formula: "(idx+K) % power",
+				Values: fmt.Sprintf("(%d%+d) %% %d = %d", idx, key, power, newIdx), Output: string(reverseAlphabetMap[newIdx])})
+		}
 	}
 
-	return string(encryptedText)
+	return string(encryptedText), nil
 }
 
-func Affine(input string, key keys.Affine, alphabetMap map[rune]int, power int) string {
+func Affine(input string, key keys.Affine, alphabetMap map[rune]int, power int) (string, error) {
+	// при K1, не взаимно простом с мощностью, разные буквы переходят в одну и расшифровать текст нельзя
+	if !modmath.Coprime(key.K1, power) {
+		return "", fmt.Errorf("%w: K1 = %d is not coprime with the power %d", ErrNotInvertible, key.K1, power)
+	}
+	if err := CheckText(input, alphabetMap); err != nil {
+		return "", err
+	}
+
 	reverseAlphabetMap := make(map[int]rune)
 
 	for char, index := range alphabetMap {
@@ -41,16 +59,25 @@ func Affine(input string, key keys.Affine, alphabetMap map[rune]int, power int)
 
 	var encryptedText []rune
 
-	for _, char := range input {
+	for i, char := range []rune(input) {
 		idx := alphabetMap[char]
-		newIdx := (key.K1*idx + key.K2) % power
+		newIdx := modmath.Mod(key.K1*idx+key.K2, power)
 		encryptedText = append(encryptedText, reverseAlphabetMap[newIdx])
+
+		if trace.Enabled() {
+			trace.Emit(trace.Step{Pos: i + 1, Input: string(char), Formula: "(K1*idx+K2) % power",
+				Values: fmt.Sprintf("(%d*%d+%d) %% %d = %d", key.K1, idx, key.K2, power, newIdx), Output: string(reverseAlphabetMap[newIdx])})
+		}
 	}
 
-	return string(encryptedText)
+	return string(encryptedText), nil
 }
 
-func Substitution(input string, key []int, alphabetMap map[rune]int, power int) string {
+func Substitution(input string, key []int, alphabetMap map[rune]int, power int) (string, error) {
+	if len(key) != power {
+		return "", fmt.Errorf("%w: substitution key has %d symbols, the alphabet has %d", ErrKeyLength, len(key), power)
+	}
+
 	// Create reverse map to find character by its numeric index
 	reverseAlphabetMap := make(map[int]rune)
 	for char, idx := range alphabetMap {
@@ -59,20 +86,24 @@ func Substitution(input string, key []int, alphabetMap map[rune]int, power int)
 
 	var encryptedText []rune
 
-	for _, char := range input {
+	for i, char := range []rune(input) {
 		// Find the index of the character in the alphabet
 		idx, ok := alphabetMap[char]
 		if !ok {
-			fmt.Printf("input contains invalid character: '%c'\n", char)
-			return ""
+			return "", &ErrInvalidRune{Rune: char, Pos: i}
 		}
 
 		// Encrypt by replacing the character with the one at the index from the key
 		encryptedChar := reverseAlphabetMap[key[idx]]
 		encryptedText = append(encryptedText, encryptedChar)
+
+		if trace.Enabled() {
+			trace.Emit(trace.Step{Pos: i + 1, Input: string(char), Formula: "key[idx]",
+				Values: fmt.Sprintf("key[%d] = %d", idx, key[idx]), Output: string(encryptedChar)})
+		}
 	}
 
-	return string(encryptedText)
+	return string(encryptedText), nil
 }
 
 func randomRune(alphabetMap map[rune]int, power int) rune {
@@ -87,18 +118,22 @@ func randomRune(alphabetMap map[rune]int, power int) rune {
 	return alphabet[i]
 }
 
-func Permutation(input, keyword string, alphabetMap map[rune]int, power int) string {
+func Permutation(input, keyword string, alphabetMap map[rune]int, power int) (string, error) {
 	cols := utf8.RuneCountInString(keyword)
+	if cols == 0 {
+		return "", fmt.Errorf("%w: permutation key is empty", ErrKeyLength)
+	}
+	if err := CheckText(input, alphabetMap); err != nil {
+		return "", err
+	}
+
 	paddingLen := cols - (utf8.RuneCountInString(input) % cols)
 	rows := utf8.RuneCountInString(input) / cols
 	if utf8.RuneCountInString(input)%cols != 0 {
 		rows = (utf8.RuneCountInString(input) + paddingLen) / cols
 	}
 
-	fmt.Println(rows, cols)
-
 	if utf8.RuneCountInString(input)%cols != 0 {
-		fmt.Println("я тут")
 		paddingChar := randomRune(alphabetMap, power)
 		for i := 0; i < paddingLen; i++ {
 			input += string(paddingChar)
@@ -131,7 +166,10 @@ func Permutation(input, keyword string, alphabetMap map[rune]int, power int) str
 		order[li.index] = sortedIndex
 	}
 
-	fmt.Println(order)
+	if trace.Enabled() {
+		trace.Emit(trace.Step{Input: keyword, Formula: "order of the key symbols in the alphabet",
+			Values: fmt.Sprintf("%d rows x %d columns", rows, cols), Output: fmt.Sprint(order)})
+	}
 
 	table := make([][]rune, rows)
 	inputRunes := []rune(input)
@@ -146,7 +184,13 @@ func Permutation(input, keyword string, alphabetMap map[rune]int, power int) str
 
 	// Переставляем элементы каждой строки в соответствии с порядком из слайса order
 	for i := 0; i < rows; i++ {
-		table[i] = rearrangeRow(table[i], order)
+		row := table[i]
+		table[i] = rearrangeRow(row, order)
+
+		if trace.Enabled() {
+			trace.Emit(trace.Step{Input: string(row), Formula: "row[i] -> column order[i]",
+				Values: fmt.Sprintf("row %d", i+1), Output: string(table[i])})
+		}
 	}
 
 	// Создаем шифротекст, проходя по строкам
@@ -159,7 +203,7 @@ func Permutation(input, keyword string, alphabetMap map[rune]int, power int) str
 		}
 	}
 
-	return cipherText.String()
+	return cipherText.String(), nil
 }
 
 func rearrangeRow(row []rune, order []int) []rune {
@@ -170,67 +214,60 @@ func rearrangeRow(row []rune, order []int) []rune {
 	return rearranged
 }
 
-func Vigenere(plaintext string, key string, alphabet map[rune]int) string {
+func Vigenere(plaintext string, key string, alphabet map[rune]int) (string, error) {
+	if key == "" {
+		return "", fmt.Errorf("%w: vigenere key is empty", ErrKeyLength)
+	}
+	if err := CheckText(plaintext, alphabet); err != nil {
+		return "", err
+	}
+
 	alphabetLength := len(alphabet)
 	reverseMap := make(map[int]rune)
 	for char, index := range alphabet {
 		reverseMap[index] = char
 	}
 
-	keyLength := len(key)
+	// ключ и текст индексируются по символам, а не по байтам
+	keyRunes := []rune(key)
+	keyLength := len(keyRunes)
 	keyIndices := make([]int, keyLength)
-	for i, char := range key {
+	for i, char := range keyRunes {
 		keyIndices[i] = alphabet[char]
 	}
 
 	encryptedText := make([]rune, 0)
 
-	for i, char := range plaintext {
+	for i, char := range []rune(plaintext) {
 		p := alphabet[char]
 		k := keyIndices[i%keyLength]
-		encryptedCharIndex := (p + k) % alphabetLength
+		encryptedCharIndex := modmath.Mod(p+k, alphabetLength)
 		encryptedText = append(encryptedText, reverseMap[encryptedCharIndex])
-	}
 
-	return string(encryptedText)
-}
-
-// Функция для нахождения обратного числа по модулю
-func modInverse(a, m int) (int, error) {
-	a = a % m
-	for x := 1; x < m; x++ {
-		if (a*x)%m == 1 {
-			return x, nil
+		if trace.Enabled() {
+			trace.Emit(trace.Step{Pos: len(encryptedText), Input: string(char), Formula: "(idx+k[i]) % power",
+				Values: fmt.Sprintf("(%d+%d) %% %d = %d", p, k, alphabetLength, encryptedCharIndex), Output: string(reverseMap[encryptedCharIndex])})
 		}
 	}
-	return 0, errors.New("no modular inverse exists")
-}
-
-func Mod(x, y int) int {
-	if x < 0 {
-		a := -x / y
-		fmt.Println(a)
 
-		return ((a)+1)*y + x
-	}
-
-	return x - (x/y)*y
+	return string(encryptedText), nil
 }
 
-func hillEncryptPair(k11, k12, k21, k22, p1, p2, power int) (int, int) {
-	c1 := Mod(k11*p1+k21*p2, power)
-	c2 := Mod(k12*p1+k22*p2, power)
-
-	return c1, c2
-}
+func Hill(input string, key [2][2]int, alphabetMap map[rune]int, power int) (string, error) {
+	det := modmath.Mod(key[0][0]*key[1][1]-key[0][1]*key[1][0], power)
+	if !modmath.Coprime(det, power) {
+		return "", fmt.Errorf("%w: hill key determinant %d is not coprime with the power %d", ErrNotInvertible, det, power)
+	}
 
-func Hill(input string, key [2][2]int, alphabetMap map[rune]int, power int) string {
 	reverseAlphabetMap := make(map[int]rune)
 	for char, idx := range alphabetMap {
 		reverseAlphabetMap[idx] = char
 	}
 
 	input = strings.ToUpper(input)
+	if err := CheckText(input, alphabetMap); err != nil {
+		return "", err
+	}
 	if utf8.RuneCountInString(input)%2 != 0 {
 		rand := randomRune(alphabetMap, power)
 		input += string(rand) // Добавляем символ для выравнивания
@@ -242,10 +279,419 @@ func Hill(input string, key [2][2]int, alphabetMap map[rune]int, power int) stri
 		p1 := alphabetMap[text[i]]
 		p2 := alphabetMap[text[i+1]]
 
-		c1, c2 := hillEncryptPair(key[0][0], key[0][1], key[1][0], key[1][1], p1, p2, power)
+		c1, c2 := modmath.MulVector(p1, p2, key, power)
 		ciphertext.WriteRune(reverseAlphabetMap[c1])
 		ciphertext.WriteRune(reverseAlphabetMap[c2])
+
+		if trace.Enabled() {
+			trace.Emit(trace.Step{Pos: i + 1, Input: string(text[i : i+2]), Formula: "(p1*K11+p2*K21, p1*K12+p2*K22) % power",
+				Values: fmt.Sprintf("(%d*%d+%d*%d, %d*%d+%d*%d) %% %d = (%d, %d)", p1, key[0][0], p2, key[1][0], p1, key[0][1], p2, key[1][1], power, c1, c2),
+				Output: string([]rune{reverseAlphabetMap[c1], reverseAlphabetMap[c2]})})
+		}
+	}
+
+	return ciphertext.String(), nil
+}
+
+// TranspositionOrder returns the positions of the input characters in the order
+// they are read out of the table. The table has len(ranks) columns and its last row may be incomplete.
+// Columns are read in ascending rank, columns with equal rank are read together row by row (Myszkowski).
+func TranspositionOrder(length int, ranks []int) []int {
+	cols := len(ranks)
+
+	// группируем столбцы с одинаковым рангом, сохраняя порядок слева направо
+	groups := make(map[int][]int)
+	var distinct []int
+	for col, rank := range ranks {
+		if _, ok := groups[rank]; !ok {
+			distinct = append(distinct, rank)
+		}
+		groups[rank] = append(groups[rank], col)
+	}
+	sort.Ints(distinct)
+
+	rows := (length + cols - 1) / cols
+	order := make([]int, 0, length)
+	for _, rank := range distinct {
+		for row := 0; row < rows; row++ {
+			for _, col := range groups[rank] {
+				pos := row*cols + col
+				if pos < length { // последняя строка может быть неполной
+					order = append(order, pos)
+				}
+			}
+		}
+	}
+
+	return order
+}
+
+// KeywordRanks returns the rank of each keyword letter by its position in the alphabet.
+// Repeated letters get different ranks from left to right.
+func KeywordRanks(keyword string, alphabetMap map[rune]int) []int {
+	runes := []rune(keyword)
+	positions := make([]int, len(runes))
+	for i := range positions {
+		positions[i] = i
+	}
+
+	sort.SliceStable(positions, func(i, j int) bool {
+		return alphabetMap[runes[positions[i]]] < alphabetMap[runes[positions[j]]]
+	})
+
+	ranks := make([]int, len(runes))
+	for rank, pos := range positions {
+		ranks[pos] = rank
+	}
+
+	return ranks
+}
+
+func transpose(input string, ranks []int) string {
+	inputRunes := []rune(input)
+
+	var cipherText strings.Builder
+	for _, pos := range TranspositionOrder(len(inputRunes), ranks) {
+		cipherText.WriteRune(inputRunes[pos])
+	}
+
+	return cipherText.String()
+}
+
+// Columnar осуществляет шифрование столбцовой перестановкой: текст записывается в таблицу по строкам
+// без дополнения и считывается по столбцам в порядке букв ключа
+func Columnar(input, keyword string, alphabetMap map[rune]int) (string, error) {
+	if keyword == "" {
+		return "", fmt.Errorf("%w: transposition key is empty", ErrKeyLength)
+	}
+	if err := CheckText(input, alphabetMap); err != nil {
+		return "", err
+	}
+
+	return transpose(input, KeywordRanks(keyword, alphabetMap)), nil
+}
+
+// Myszkowski осуществляет шифрование перестановкой Мышковского: столбцы с одинаковыми буквами ключа
+// считываются вместе построчно слева направо
+func Myszkowski(input, keyword string, alphabetMap map[rune]int) (string, error) {
+	if keyword == "" {
+		return "", fmt.Errorf("%w: transposition key is empty", ErrKeyLength)
+	}
+	if err := CheckText(input, alphabetMap); err != nil {
+		return "", err
+	}
+
+	ranks := make([]int, 0, utf8.RuneCountInString(keyword))
+	for _, char := range keyword {
+		ranks = append(ranks, alphabetMap[char])
+	}
+
+	return transpose(input, ranks), nil
+}
+
+// DoubleTransposition осуществляет двойную столбцовую перестановку с двумя ключами
+func DoubleTransposition(input string, key keys.DoubleTransposition, alphabetMap map[rune]int) (string, error) {
+	first, err := Columnar(input, key.First, alphabetMap)
+	if err != nil {
+		return "", err
+	}
+	return Columnar(first, key.Second, alphabetMap)
+}
+
+// Enigma осуществляет шифрование роторной машиной. Ключ должен быть проверен verify.EnigmaKey
+func Enigma(input string, key keys.Enigma, alphabetMap map[rune]int, power int) (string, error) {
+	if err := CheckText(input, alphabetMap); err != nil {
+		return "", err
+	}
+
+	machine, err := enigma.New(key, alphabetMap, power)
+	if err != nil {
+		return "", err
+	}
+
+	return machine.Encrypt(input), nil
+}
+
+// Homophonic осуществляет омофонное шифрование: каждый символ заменяется случайно выбранным
+// кодом из своего набора, коды записываются подряд числами одинаковой ширины
+func Homophonic(input string, key keys.Homophonic) (string, error) {
+	width := key.Width()
+
+	var cipherText strings.Builder
+	for i, char := range []rune(input) {
+		codes, ok := key.Codes[char]
+		if !ok {
+			return "", &ErrInvalidRune{Rune: char, Pos: i}
+		}
+
+		choice, err := keygen.Intn(len(codes))
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&cipherText, "%0*d", width, codes[choice])
+	}
+
+	return cipherText.String(), nil
+}
+
+// Trithemius осуществляет шифрование Тритемия: сдвиг по tabula recta растет на key.Step после каждой буквы
+func Trithemius(input string, key keys.Trithemius, alphabetMap map[rune]int, power int) (string, error) {
+	if err := CheckText(input, alphabetMap); err != nil {
+		return "", err
 	}
 
-	return ciphertext.String()
+	reverseAlphabetMap := make(map[int]rune)
+	for char, index := range alphabetMap {
+		reverseAlphabetMap[index] = char
+	}
+
+	var encryptedText []rune
+	shift := key.Shift
+	for i, char := range []rune(input) {
+		idx := alphabetMap[char]
+		newIdx := modmath.Mod(idx+shift, power)
+		encryptedText = append(encryptedText, reverseAlphabetMap[newIdx])
+
+		if trace.Enabled() {
+			trace.Emit(trace.Step{Pos: i + 1, Input: string(char), Formula: "(idx+shift) % power",
+				Values: fmt.Sprintf("(%d+%d) %% %d = %d", idx, shift, power, newIdx), Output: string(reverseAlphabetMap[newIdx])})
+		}
+		shift = modmath.Mod(shift+key.Step, power)
+	}
+
+	return string(encryptedText), nil
+}
+
+// Alberti осуществляет шифрование диском Альберти. Перед каждой группой из key.Period букв
+// внутренний диск поворачивается случайно, и в шифротекст записывается буква внешнего диска,
+// напротив которой встала индексная буква
+func Alberti(input string, key keys.Alberti, alphabetMap map[rune]int, power int) (string, error) {
+	if err := CheckText(input, alphabetMap); err != nil {
+		return "", err
+	}
+
+	reverseAlphabetMap := make(map[int]rune)
+	for char, index := range alphabetMap {
+		reverseAlphabetMap[index] = char
+	}
+
+	indexPos := 0
+	for i, char := range key.Inner {
+		if char == key.Index {
+			indexPos = i
+		}
+	}
+
+	var encryptedText []rune
+	offset := 0
+	for i, char := range []rune(input) {
+		if i%key.Period == 0 {
+			outer, err := keygen.Intn(power)
+			if err != nil {
+				return "", err
+			}
+			offset = indexPos - outer
+			encryptedText = append(encryptedText, reverseAlphabetMap[outer])
+
+			if trace.Enabled() {
+				trace.Emit(trace.Step{Pos: i + 1, Input: string(key.Index), Formula: "offset = index - outer",
+					Values: fmt.Sprintf("%d-%d = %d", indexPos, outer, offset), Output: string(reverseAlphabetMap[outer])})
+			}
+		}
+
+		// буква внешнего диска заменяется буквой внутреннего диска, стоящей напротив нее
+		idx := alphabetMap[char]
+		innerPos := modmath.Mod(idx+offset, power)
+		encryptedText = append(encryptedText, key.Inner[innerPos])
+
+		if trace.Enabled() {
+			trace.Emit(trace.Step{Pos: i + 1, Input: string(char), Formula: "inner[(idx+offset) % power]",
+				Values: fmt.Sprintf("inner[(%d%+d) %% %d = %d]", idx, offset, power, innerPos), Output: string(key.Inner[innerPos])})
+		}
+	}
+
+	return string(encryptedText), nil
+}
+
+// Tableau encrypts with a polyalphabetic tableau built from the plaintext and ciphertext alphabets.
+// The row for an indicator letter is the ciphertext alphabet shifted so that the indicator letter
+// stands under the first letter of the plaintext alphabet. With both alphabets unkeyed it is the Vigenere cipher.
+func Tableau(input string, plain, cipher []rune, indicator string) (string, error) {
+	if indicator == "" {
+		return "", fmt.Errorf("%w: indicator is empty", ErrKeyLength)
+	}
+
+	plainMap := make(map[rune]int)
+	for i, char := range plain {
+		plainMap[char] = i
+	}
+	cipherMap := make(map[rune]int)
+	for i, char := range cipher {
+		cipherMap[char] = i
+	}
+
+	power := len(cipher)
+	indicatorRunes := []rune(indicator)
+
+	encryptedText := make([]rune, 0, utf8.RuneCountInString(input))
+	for i, char := range []rune(input) {
+		idx, ok := plainMap[char]
+		if !ok {
+			return "", &ErrInvalidRune{Rune: char, Pos: i}
+		}
+		shift := cipherMap[indicatorRunes[i%len(indicatorRunes)]]
+		encryptedText = append(encryptedText, cipher[modmath.Mod(idx+shift, power)])
+	}
+
+	return string(encryptedText), nil
+}
+
+// Quagmire осуществляет шифрование Quagmire I-IV
+func Quagmire(input string, key keys.Quagmire) (string, error) {
+	return Tableau(input, key.Plain, key.Cipher, key.Indicator)
+}
+
+// Porta осуществляет шифрование Порта. Алфавит делится на две половины, буква ключа выбирает
+// сдвиг второй половины, и буквы из разных половин меняются местами, поэтому шифр самообратный
+func Porta(input, keyword string, alphabetMap map[rune]int, power int) (string, error) {
+	if keyword == "" {
+		return "", fmt.Errorf("%w: porta key is empty", ErrKeyLength)
+	}
+	if err := CheckText(input, alphabetMap); err != nil {
+		return "", err
+	}
+
+	reverseAlphabetMap := make(map[int]rune)
+	for char, index := range alphabetMap {
+		reverseAlphabetMap[index] = char
+	}
+
+	half := power / 2
+	keyRunes := []rune(keyword)
+
+	encryptedText := make([]rune, 0, utf8.RuneCountInString(input))
+	for i, char := range []rune(input) {
+		shift := alphabetMap[keyRunes[i%len(keyRunes)]] / 2 // соседние буквы ключа задают одну таблицу
+
+		idx := alphabetMap[char]
+		var newIdx int
+		if idx < half {
+			newIdx = half + modmath.Mod(idx+shift, half)
+		} else {
+			newIdx = modmath.Mod(idx-half-shift, half)
+		}
+		encryptedText = append(encryptedText, reverseAlphabetMap[newIdx])
+	}
+
+	return string(encryptedText), nil
+}
+
+// VICDigitOrder ranks digits the way the VIC cipher does: 1 is the smallest and 0 is the largest
+var VICDigitOrder = map[rune]int{'1': 0, '2': 1, '3': 2, '4': 3, '5': 4, '6': 5, '7': 6, '8': 7, '9': 8, '0': 9}
+
+// ChainAddition extends the seed with the lagged Fibonacci generator x[i+m] = (x[i] + x[i+1]) mod 10
+// and returns n digits that follow the seed
+func ChainAddition(seed string, n int) []int {
+	chain := make([]int, 0, len(seed)+n)
+	for _, r := range seed {
+		chain = append(chain, int(r-'0'))
+	}
+	for i := 0; len(chain) < len(seed)+n; i++ {
+		chain = append(chain, (chain[i]+chain[i+1])%10)
+	}
+	return chain[len(seed):]
+}
+
+// Checkerboard превращает текст в поток цифр по таблице: буквы верхней строки кодируются одной цифрой,
+// остальные - цифрой пустой клетки и номером столбца
+func Checkerboard(input string, board keys.Checkerboard) (string, error) {
+	codes := make(map[rune]string)
+	for col, char := range board.Rows[0] {
+		if char != 0 {
+			codes[char] = strconv.Itoa(col)
+		}
+	}
+	for i, row := range board.Rows[1:] {
+		for col, char := range row {
+			if char != 0 {
+				codes[char] = strconv.Itoa(board.Blanks[i]) + strconv.Itoa(col)
+			}
+		}
+	}
+
+	var stream strings.Builder
+	for i, char := range []rune(input) {
+		code, ok := codes[char]
+		if !ok {
+			return "", &ErrInvalidRune{Rune: char, Pos: i}
+		}
+		stream.WriteString(code)
+	}
+	return stream.String(), nil
+}
+
+// VIC осуществляет упрощенное шифрование VIC: таблица, сложение с гаммой цепного сложения
+// без переноса и столбцовая перестановка цифр
+func VIC(input string, key keys.VIC) (string, error) {
+	stream, err := Checkerboard(input, key.Board)
+	if err != nil {
+		return "", err
+	}
+
+	if key.Seed != "" {
+		gamma := ChainAddition(key.Seed, len(stream))
+		added := []byte(stream)
+		for i := range added {
+			added[i] = byte('0' + (int(added[i]-'0')+gamma[i])%10)
+		}
+		stream = string(added)
+	}
+
+	if key.Transposition != "" {
+		stream = transpose(stream, KeywordRanks(key.Transposition, VICDigitOrder))
+	}
+
+	return stream, nil
+}
+
+// Vernam осуществляет шифрование Вернама над алфавитом: к индексу каждой буквы прибавляется
+// индекс буквы гаммы по модулю мощности алфавита. Гамма должна быть не короче текста
+func Vernam(input, pad string, alphabetMap map[rune]int, power int) (string, error) {
+	if utf8.RuneCountInString(pad) < utf8.RuneCountInString(input) {
+		return "", fmt.Errorf("%w: pad of %d symbols is shorter than the text of %d", ErrKeyLength, utf8.RuneCountInString(pad), utf8.RuneCountInString(input))
+	}
+	if err := CheckText(input, alphabetMap); err != nil {
+		return "", err
+	}
+	if err := CheckText(pad, alphabetMap); err != nil {
+		return "", fmt.Errorf("pad: %w", err)
+	}
+
+	reverseAlphabetMap := make(map[int]rune)
+	for char, index := range alphabetMap {
+		reverseAlphabetMap[index] = char
+	}
+
+	padRunes := []rune(pad)
+	encryptedText := make([]rune, 0, len(padRunes))
+	for i, char := range []rune(input) {
+		idx := modmath.Mod(alphabetMap[char]+alphabetMap[padRunes[i]], power)
+		encryptedText = append(encryptedText, reverseAlphabetMap[idx])
+
+		if trace.Enabled() {
+			trace.Emit(trace.Step{Pos: i + 1, Input: string(char), Formula: "(idx+pad[i]) % power",
+				Values: fmt.Sprintf("(%d+%d) %% %d = %d", alphabetMap[char], alphabetMap[padRunes[i]], power, idx), Output: string(reverseAlphabetMap[idx])})
+		}
+	}
+
+	return string(encryptedText), nil
+}
+
+// XOR складывает байты текста с байтами гаммы по модулю 2, шифрование и дешифрование совпадают
+func XOR(input, pad []byte) []byte {
+	output := make([]byte, len(input))
+	for i := range input {
+		output[i] = input[i] ^ pad[i]
+	}
+	return output
 }
@@ -0,0 +1,37 @@
+package encrypt
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/marelinaa/cipher-algorithms/modmath"
+)
+
+// ErrNotInvertible is returned when a key has no inverse modulo the power of the alphabet,
+// it is the same error as modmath.ErrNotInvertible
+var ErrNotInvertible = modmath.ErrNotInvertible
+
+// ErrKeyLength is returned when the key is too short or too long for the cipher
+var ErrKeyLength = errors.New("key has a wrong length")
+
+// ErrInvalidRune reports a symbol of the text that the cipher can not process
+type ErrInvalidRune struct {
+	Rune rune
+	Pos  int // position of the symbol in the text starting from 0
+}
+
+func (e *ErrInvalidRune) Error() string {
+	return fmt.Sprintf("text contains invalid character %q at position %d", e.Rune, e.Pos)
+}
+
+// CheckText checks that every symbol of the text is in the alphabet
+func CheckText(input string, alphabetMap map[rune]int) error {
+	pos := 0
+	for _, char := range input {
+		if _, ok := alphabetMap[char]; !ok {
+			return &ErrInvalidRune{Rune: char, Pos: pos}
+		}
+		pos++
+	}
+	return nil
+}
@@ -0,0 +1,310 @@
+package encrypt
+
+import (
+	"crypto/cipher"
+	"crypto/rand"
+	"fmt"
+	"strings"
+
+	"github.com/marelinaa/cipher-algorithms/keygen"
+	"github.com/marelinaa/cipher-algorithms/modmath"
+)
+
+// Modes of operation, the IV is generated randomly and written before the ciphertext
+const (
+	ModeECB = "ecb"
+	ModeCBC = "cbc"
+	ModeCFB = "cfb"
+	ModeOFB = "ofb"
+	ModeCTR = "ctr"
+)
+
+var Modes = []string{ModeECB, ModeCBC, ModeCFB, ModeOFB, ModeCTR}
+
+// IVSize returns the IV length in bytes for the mode: CTR takes half a block
+// as in GOST R 34.13-2015, ECB takes no IV
+func IVSize(mode string, blockSize int) int {
+	switch mode {
+	case ModeECB:
+		return 0
+	case ModeCTR:
+		return blockSize / 2
+	}
+	return blockSize
+}
+
+// Pad дополняет данные до целого числа блоков по процедуре 2 ГОСТ Р 34.13-2015:
+// добавляется байт 0x80 и нули, дополнение добавляется всегда
+func Pad(data []byte, blockSize int) []byte {
+	padded := append([]byte{}, data...)
+	padded = append(padded, 0x80)
+	for len(padded)%blockSize != 0 {
+		padded = append(padded, 0)
+	}
+	return padded
+}
+
+func xorBytes(dst, a, b []byte) {
+	for i := range dst {
+		dst[i] = a[i] ^ b[i]
+	}
+}
+
+// ECB осуществляет шифрование в режиме простой замены: каждый блок шифруется независимо
+func ECB(b cipher.Block, plaintext []byte) []byte {
+	size := b.BlockSize()
+	ciphertext := Pad(plaintext, size)
+	for i := 0; i < len(ciphertext); i += size {
+		b.Encrypt(ciphertext[i:i+size], ciphertext[i:i+size])
+	}
+	return ciphertext
+}
+
+// CBC осуществляет шифрование в режиме сцепления блоков: C_i = E(P_i xor C_{i-1}), C_0 = IV
+func CBC(b cipher.Block, iv, plaintext []byte) []byte {
+	size := b.BlockSize()
+	ciphertext := Pad(plaintext, size)
+	prev := iv
+	for i := 0; i < len(ciphertext); i += size {
+		block := ciphertext[i : i+size]
+		xorBytes(block, block, prev)
+		b.Encrypt(block, block)
+		prev = block
+	}
+	return ciphertext
+}
+
+// CFB осуществляет шифрование в режиме обратной связи по шифротексту: C_i = P_i xor E(C_{i-1})
+func CFB(b cipher.Block, iv, plaintext []byte) []byte {
+	size := b.BlockSize()
+	ciphertext := make([]byte, len(plaintext))
+	gamma := make([]byte, size)
+	prev := iv
+	for i := 0; i < len(plaintext); i += size {
+		end := min(i+size, len(plaintext))
+		b.Encrypt(gamma, prev)
+		xorBytes(ciphertext[i:end], plaintext[i:end], gamma)
+		prev = ciphertext[i:end]
+	}
+	return ciphertext
+}
+
+// OFB осуществляет шифрование в режиме обратной связи по выходу: O_i = E(O_{i-1}), C_i = P_i xor O_i.
+// Шифрование и дешифрование совпадают
+func OFB(b cipher.Block, iv, plaintext []byte) []byte {
+	size := b.BlockSize()
+	ciphertext := make([]byte, len(plaintext))
+	gamma := append([]byte{}, iv...)
+	for i := 0; i < len(plaintext); i += size {
+		end := min(i+size, len(plaintext))
+		b.Encrypt(gamma, gamma)
+		xorBytes(ciphertext[i:end], plaintext[i:end], gamma)
+	}
+	return ciphertext
+}
+
+// CTR осуществляет шифрование в режиме гаммирования: счетчик IV||0...0 шифруется и увеличивается на 1.
+// Шифрование и дешифрование совпадают
+func CTR(b cipher.Block, iv, plaintext []byte) []byte {
+	size := b.BlockSize()
+	ciphertext := make([]byte, len(plaintext))
+	counter := make([]byte, size)
+	copy(counter, iv)
+	gamma := make([]byte, size)
+	for i := 0; i < len(plaintext); i += size {
+		end := min(i+size, len(plaintext))
+		b.Encrypt(gamma, counter)
+		xorBytes(ciphertext[i:end], plaintext[i:end], gamma)
+
+		for j := size - 1; j >= 0; j-- {
+			counter[j]++
+			if counter[j] != 0 {
+				break
+			}
+		}
+	}
+	return ciphertext
+}
+
+// BlockMode encrypts in the mode with a random IV written before the ciphertext
+func BlockMode(b cipher.Block, mode string, plaintext []byte) ([]byte, error) {
+	iv := make([]byte, IVSize(mode, b.BlockSize()))
+	_, err := rand.Read(iv)
+	if err != nil {
+		return nil, err
+	}
+
+	var ciphertext []byte
+	switch mode {
+	case ModeECB:
+		ciphertext = ECB(b, plaintext)
+	case ModeCBC:
+		ciphertext = CBC(b, iv, plaintext)
+	case ModeCFB:
+		ciphertext = CFB(b, iv, plaintext)
+	case ModeOFB:
+		ciphertext = OFB(b, iv, plaintext)
+	case ModeCTR:
+		ciphertext = CTR(b, iv, plaintext)
+	default:
+		return nil, fmt.Errorf("unknown mode of operation: %s", mode)
+	}
+
+	return append(iv, ciphertext...), nil
+}
+
+// IndexCipher encrypts blocks of alphabet indices the way a block cipher encrypts blocks of bytes
+type IndexCipher interface {
+	BlockSize() int
+	Power() int
+	Encrypt(dst, src []int)
+	Decrypt(dst, src []int)
+}
+
+type hillCipher struct {
+	key     [2][2]int
+	inverse [2][2]int
+	power   int
+}
+
+// NewHillCipher creates the Hill cipher over pairs of alphabet indices
+func NewHillCipher(key [2][2]int, power int) (IndexCipher, error) {
+	inverse, err := modmath.InverseMatrix(key, power)
+	if err != nil {
+		return nil, fmt.Errorf("hill key is not invertible: %w", err)
+	}
+	return &hillCipher{key: key, inverse: inverse, power: power}, nil
+}
+
+func (h *hillCipher) BlockSize() int {
+	return 2
+}
+
+func (h *hillCipher) Power() int {
+	return h.power
+}
+
+// multiply multiplies the row vector by the matrix, as encrypt.Hill does
+func (h *hillCipher) multiply(dst, src []int, m [2][2]int) {
+	dst[0], dst[1] = modmath.MulVector(src[0], src[1], m, h.power)
+}
+
+func (h *hillCipher) Encrypt(dst, src []int) {
+	h.multiply(dst, src, h.key)
+}
+
+func (h *hillCipher) Decrypt(dst, src []int) {
+	h.multiply(dst, src, h.inverse)
+}
+
+func addIndices(dst, a, b []int, power int) {
+	for i := range dst {
+		dst[i] = modmath.Mod(a[i]+b[i], power)
+	}
+}
+
+// IndexMode encrypts alphabet indices in the mode. The random IV consists of alphabet indices and is
+// written before the ciphertext. ECB and CBC pad the last block with random symbols like encrypt.Hill,
+// in the chained modes the previous block is added modulo the power instead of xor
+func IndexMode(c IndexCipher, mode string, plaintext []int) ([]int, error) {
+	size, power := c.BlockSize(), c.Power()
+
+	// счетчик занимает весь блок: половина блока из двух индексов слишком мала
+	ivSize := size
+	if mode == ModeECB {
+		ivSize = 0
+	}
+	iv := make([]int, ivSize)
+	for i := range iv {
+		var err error
+		iv[i], err = keygen.Intn(power)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if mode == ModeECB || mode == ModeCBC {
+		for len(plaintext)%size != 0 {
+			r, err := keygen.Intn(power)
+			if err != nil {
+				return nil, err
+			}
+			plaintext = append(plaintext, r)
+		}
+	}
+
+	ciphertext := make([]int, len(plaintext))
+	prev := append([]int{}, iv...)
+	gamma := make([]int, size)
+	for i := 0; i < len(plaintext); i += size {
+		end := min(i+size, len(plaintext))
+		block := ciphertext[i:end]
+
+		switch mode {
+		case ModeECB:
+			c.Encrypt(block, plaintext[i:end])
+		case ModeCBC:
+			addIndices(block, plaintext[i:end], prev, power)
+			c.Encrypt(block, block)
+			prev = block
+		case ModeCFB:
+			c.Encrypt(gamma, prev)
+			addIndices(block, plaintext[i:end], gamma, power)
+			prev = block
+		case ModeOFB:
+			c.Encrypt(prev, prev)
+			addIndices(block, plaintext[i:end], prev, power)
+		case ModeCTR:
+			c.Encrypt(gamma, prev)
+			addIndices(block, plaintext[i:end], gamma, power)
+			IncrementIndices(prev, power)
+		default:
+			return nil, fmt.Errorf("unknown mode of operation: %s", mode)
+		}
+	}
+
+	return append(iv, ciphertext...), nil
+}
+
+// IncrementIndices adds 1 to the block treated as a number in base power, the last index is the lowest digit
+func IncrementIndices(block []int, power int) {
+	for j := len(block) - 1; j >= 0; j-- {
+		block[j] = modmath.Mod(block[j]+1, power)
+		if block[j] != 0 {
+			break
+		}
+	}
+}
+
+// HillMode осуществляет шифрование Хилла в заданном режиме сцепления
+func HillMode(input string, key [2][2]int, mode string, alphabetMap map[rune]int, power int) (string, error) {
+	c, err := NewHillCipher(key, power)
+	if err != nil {
+		return "", err
+	}
+	if err := CheckText(input, alphabetMap); err != nil {
+		return "", err
+	}
+
+	plaintext := make([]int, 0, len(input))
+	for _, char := range input {
+		plaintext = append(plaintext, alphabetMap[char])
+	}
+
+	ciphertext, err := IndexMode(c, mode, plaintext)
+	if err != nil {
+		return "", err
+	}
+
+	reverseAlphabetMap := make(map[int]rune)
+	for char, idx := range alphabetMap {
+		reverseAlphabetMap[idx] = char
+	}
+
+	var cipherText strings.Builder
+	for _, idx := range ciphertext {
+		cipherText.WriteRune(reverseAlphabetMap[idx])
+	}
+
+	return cipherText.String(), nil
+}
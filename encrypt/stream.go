@@ -0,0 +1,40 @@
+package encrypt
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/marelinaa/cipher-algorithms/modmath"
+	"github.com/marelinaa/cipher-algorithms/stream"
+	"github.com/marelinaa/cipher-algorithms/trace"
+)
+
+// Stream осуществляет шифрование Вернама над алфавитом с гаммой от генератора:
+// к индексу каждого символа прибавляется очередное значение гаммы по модулю мощности, как в шифре Виженера
+func Stream(input string, g stream.Generator, alphabetMap map[rune]int, power int) (string, error) {
+	if err := CheckText(input, alphabetMap); err != nil {
+		return "", err
+	}
+
+	reverseAlphabetMap := make(map[int]rune)
+	for char, index := range alphabetMap {
+		reverseAlphabetMap[index] = char
+	}
+
+	var cipherText strings.Builder
+	for i, char := range []rune(input) {
+		gamma, err := stream.Index(g, power)
+		if err != nil {
+			return "", err
+		}
+		idx := modmath.Mod(alphabetMap[char]+gamma, power)
+		cipherText.WriteRune(reverseAlphabetMap[idx])
+
+		if trace.Enabled() {
+			trace.Emit(trace.Step{Pos: i + 1, Input: string(char), Formula: "(idx+gamma) % power",
+				Values: fmt.Sprintf("(%d+%d) %% %d = %d", alphabetMap[char], gamma, power, idx), Output: string(reverseAlphabetMap[idx])})
+		}
+	}
+
+	return cipherText.String(), nil
+}
@@ -0,0 +1,363 @@
+package enigma
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/marelinaa/cipher-algorithms/keys"
+)
+
+// Latin is the alphabet the historical rotors are wired for
+const Latin = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+// Rotor describes the wiring of a rotor and the letters at which it moves the next rotor.
+// Static rotors (the M4 greek rotors) never step.
+type Rotor struct {
+	Wiring  string
+	Notches string
+	Static  bool
+}
+
+// Rotors contains the historical Enigma rotors
+var Rotors = map[string]Rotor{
+	"I":     {Wiring: "EKMFLGDQVZNTOWYHXUSPAIBRCJ", Notches: "Q"},
+	"II":    {Wiring: "AJDKSIRUXBLHWTMCQGZNPYFVOE", Notches: "E"},
+	"III":   {Wiring: "BDFHJLCPRTXVZNYEIWGAKMUSQO", Notches: "V"},
+	"IV":    {Wiring: "ESOVPZJAYQUIRHXLNFTGKDCMWB", Notches: "J"},
+	"V":     {Wiring: "VZBRGITYUPSDNHLXAWMJQOFECK", Notches: "Z"},
+	"VI":    {Wiring: "JPGVOUMFYQBENHZRDKASXLICTW", Notches: "ZM"},
+	"VII":   {Wiring: "NZJHGRCXMYSWBOUFAIVLPEKQDT", Notches: "ZM"},
+	"VIII":  {Wiring: "FKQHTLXOCBJSPDZRAMEWNIUYGV", Notches: "ZM"},
+	"Beta":  {Wiring: "LEYJVCNIXWPBQMDRTAKZGFUHOS", Static: true},
+	"Gamma": {Wiring: "FSOKANUERHMBTIYCWLQPZXVGJD", Static: true},
+}
+
+// Reflectors contains the historical Enigma reflectors
+var Reflectors = map[string]string{
+	"A":      "EJMZALYXVBWFCRQUONTSPIKHGD",
+	"B":      "YRUHQSLDPXNGOKMIEBFZCWVJAT",
+	"C":      "FVPJIAOYEDRZXWGCTKUQSBNMHL",
+	"B-thin": "ENKQAUYWJICOPBLMDXZVFTHRGS",
+	"C-thin": "RDOBJNTKVEHMLFCWZAXGYIPSUQ",
+}
+
+// Model lists the rotors and reflectors a historical machine could be assembled from
+type Model struct {
+	Greek      []string // rotors allowed in the leftmost (fourth) slot
+	Rotors     []string
+	Reflectors []string
+}
+
+// Custom is the model name for machines with rotors and reflector wired over any alphabet
+const Custom = "custom"
+
+var Models = map[string]Model{
+	"I": {
+		Rotors:     []string{"I", "II", "III", "IV", "V"},
+		Reflectors: []string{"A", "B", "C"},
+	},
+	"M3": {
+		Rotors:     []string{"I", "II", "III", "IV", "V", "VI", "VII", "VIII"},
+		Reflectors: []string{"B", "C"},
+	},
+	"M4": {
+		Greek:      []string{"Beta", "Gamma"},
+		Rotors:     []string{"I", "II", "III", "IV", "V", "VI", "VII", "VIII"},
+		Reflectors: []string{"B-thin", "C-thin"},
+	},
+}
+
+type rotor struct {
+	forward  []int
+	backward []int
+	notches  map[int]bool
+	static   bool
+	ring     int
+	pos      int
+}
+
+// Machine is an assembled rotor machine. Rotors are stored from left to right.
+type Machine struct {
+	rotors      []*rotor
+	reflector   []int
+	plugboard   []int
+	alphabet    []rune
+	alphabetMap map[rune]int
+	power       int
+}
+
+func contains(list []string, name string) bool {
+	for _, s := range list {
+		if s == name {
+			return true
+		}
+	}
+	return false
+}
+
+// permutation converts a wiring string into a permutation of alphabet indices
+func permutation(wiring string, alphabetMap map[rune]int, power int) ([]int, error) {
+	if utf8.RuneCountInString(wiring) != power {
+		return nil, fmt.Errorf("wiring %q must contain %d symbols", wiring, power)
+	}
+
+	perm := make([]int, 0, power)
+	seen := make(map[int]bool)
+	for _, r := range wiring {
+		idx, ok := alphabetMap[r]
+		if !ok {
+			return nil, fmt.Errorf("wiring contains invalid symbol: '%c'", r)
+		}
+		if seen[idx] {
+			return nil, fmt.Errorf("wiring contains duplicate symbol: '%c'", r)
+		}
+		seen[idx] = true
+		perm = append(perm, idx)
+	}
+
+	return perm, nil
+}
+
+// parseRotor returns a historical rotor by name or parses a custom rotor written as WIRING/NOTCHES
+func parseRotor(spec, model string) (Rotor, error) {
+	if model != Custom {
+		r, ok := Rotors[spec]
+		if !ok {
+			return Rotor{}, fmt.Errorf("unknown rotor: %s", spec)
+		}
+		return r, nil
+	}
+
+	if r, ok := Rotors[spec]; ok {
+		return r, nil
+	}
+
+	wiring, notches, _ := strings.Cut(spec, "/")
+
+	return Rotor{Wiring: wiring, Notches: notches}, nil
+}
+
+// New assembles a machine from the key. Historical models require the Latin alphabet.
+func New(key keys.Enigma, alphabetMap map[rune]int, power int) (*Machine, error) {
+	model, historical := Models[key.Model]
+	if !historical && key.Model != Custom {
+		return nil, fmt.Errorf("unknown enigma model: %s", key.Model)
+	}
+
+	if historical {
+		if power != len(Latin) {
+			return nil, fmt.Errorf("enigma %s requires the alphabet %s", key.Model, Latin)
+		}
+		for i, r := range Latin {
+			if idx, ok := alphabetMap[r]; !ok || idx != i {
+				return nil, fmt.Errorf("enigma %s requires the alphabet %s", key.Model, Latin)
+			}
+		}
+
+		slots := 3
+		if model.Greek != nil {
+			slots = 4
+		}
+		if len(key.Rotors) != slots {
+			return nil, fmt.Errorf("enigma %s takes %d rotors", key.Model, slots)
+		}
+
+		for i, name := range key.Rotors {
+			allowed := model.Rotors
+			if model.Greek != nil && i == 0 {
+				allowed = model.Greek
+			}
+			if !contains(allowed, name) {
+				return nil, fmt.Errorf("rotor %s can not be used in slot %d of enigma %s", name, i+1, key.Model)
+			}
+		}
+
+		if !contains(model.Reflectors, key.Reflector) {
+			return nil, fmt.Errorf("reflector %s can not be used in enigma %s", key.Reflector, key.Model)
+		}
+	}
+
+	if len(key.Rotors) == 0 {
+		return nil, fmt.Errorf("enigma needs at least one rotor")
+	}
+
+	m := &Machine{
+		alphabet:    make([]rune, power),
+		alphabetMap: alphabetMap,
+		power:       power,
+	}
+	for char, idx := range alphabetMap {
+		m.alphabet[idx] = char
+	}
+
+	rings := []rune(key.Rings)
+	positions := []rune(key.Positions)
+	if len(rings) != len(key.Rotors) || len(positions) != len(key.Rotors) {
+		return nil, fmt.Errorf("ring settings and positions must contain one symbol per rotor")
+	}
+
+	for i, spec := range key.Rotors {
+		r, err := parseRotor(spec, key.Model)
+		if err != nil {
+			return nil, err
+		}
+
+		forward, err := permutation(r.Wiring, alphabetMap, power)
+		if err != nil {
+			return nil, err
+		}
+
+		backward := make([]int, power)
+		for in, out := range forward {
+			backward[out] = in
+		}
+
+		notches := make(map[int]bool)
+		for _, n := range r.Notches {
+			idx, ok := alphabetMap[n]
+			if !ok {
+				return nil, fmt.Errorf("notch contains invalid symbol: '%c'", n)
+			}
+			notches[idx] = true
+		}
+
+		ring, ok := alphabetMap[rings[i]]
+		if !ok {
+			return nil, fmt.Errorf("ring setting contains invalid symbol: '%c'", rings[i])
+		}
+		pos, ok := alphabetMap[positions[i]]
+		if !ok {
+			return nil, fmt.Errorf("rotor position contains invalid symbol: '%c'", positions[i])
+		}
+
+		m.rotors = append(m.rotors, &rotor{
+			forward:  forward,
+			backward: backward,
+			notches:  notches,
+			static:   r.Static,
+			ring:     ring,
+			pos:      pos,
+		})
+	}
+
+	reflectorWiring, ok := Reflectors[key.Reflector]
+	if !ok {
+		if key.Model != Custom {
+			return nil, fmt.Errorf("unknown reflector: %s", key.Reflector)
+		}
+		reflectorWiring = key.Reflector
+	}
+
+	reflector, err := permutation(reflectorWiring, alphabetMap, power)
+	if err != nil {
+		return nil, err
+	}
+
+	// отражатель должен быть инволюцией, иначе шифр не будет самообратным;
+	// при нечетной мощности алфавита один символ неизбежно отражается сам в себя
+	fixed := 0
+	for in, out := range reflector {
+		if reflector[out] != in {
+			return nil, fmt.Errorf("reflector must swap symbols in pairs")
+		}
+		if in == out {
+			fixed++
+		}
+	}
+	if fixed > power%2 {
+		return nil, fmt.Errorf("reflector can not map more than %d symbols to themselves", power%2)
+	}
+	m.reflector = reflector
+
+	m.plugboard = make([]int, power)
+	for i := range m.plugboard {
+		m.plugboard[i] = i
+	}
+
+	plugs := []rune(key.Plugboard)
+	if len(plugs)%2 != 0 {
+		return nil, fmt.Errorf("plugboard must consist of pairs of symbols")
+	}
+	for i := 0; i < len(plugs); i += 2 {
+		a, okA := alphabetMap[plugs[i]]
+		b, okB := alphabetMap[plugs[i+1]]
+		if !okA || !okB {
+			return nil, fmt.Errorf("plugboard contains invalid symbol")
+		}
+		if a == b || m.plugboard[a] != a || m.plugboard[b] != b {
+			return nil, fmt.Errorf("plugboard connects symbol more than once")
+		}
+		m.plugboard[a], m.plugboard[b] = b, a
+	}
+
+	return m, nil
+}
+
+// step moves the rotors before each key press, including the double step of the middle rotor
+func (m *Machine) step() {
+	var moving []*rotor
+	for i := len(m.rotors) - 1; i >= 0; i-- {
+		if !m.rotors[i].static {
+			moving = append(moving, m.rotors[i]) // справа налево
+		}
+	}
+	if len(moving) == 0 {
+		return
+	}
+
+	steps := make([]bool, len(moving))
+	steps[0] = true
+	for i := 1; i < len(moving); i++ {
+		if moving[i-1].notches[moving[i-1].pos] {
+			steps[i] = true
+			steps[i-1] = true // двойной шаг среднего ротора
+		}
+	}
+
+	for i, r := range moving {
+		if steps[i] {
+			r.pos = (r.pos + 1) % m.power
+		}
+	}
+}
+
+func (m *Machine) through(r *rotor, c int, wiring []int) int {
+	shift := r.pos - r.ring
+	c = wiring[((c+shift)%m.power+m.power)%m.power]
+	return ((c-shift)%m.power + m.power) % m.power
+}
+
+// Press steps the rotors and returns the index the signal for idx lights up
+func (m *Machine) Press(idx int) int {
+	m.step()
+
+	c := m.plugboard[idx]
+	for i := len(m.rotors) - 1; i >= 0; i-- {
+		c = m.through(m.rotors[i], c, m.rotors[i].forward)
+	}
+	c = m.reflector[c]
+	for _, r := range m.rotors {
+		c = m.through(r, c, r.backward)
+	}
+
+	return m.plugboard[c]
+}
+
+// Positions returns the current letters shown in the rotor windows
+func (m *Machine) Positions() string {
+	var positions strings.Builder
+	for _, r := range m.rotors {
+		positions.WriteRune(m.alphabet[r.pos])
+	}
+	return positions.String()
+}
+
+// Encrypt runs the text through the machine. The same call with the same key decrypts.
+func (m *Machine) Encrypt(input string) string {
+	var output strings.Builder
+	for _, char := range input {
+		output.WriteRune(m.alphabet[m.Press(m.alphabetMap[char])])
+	}
+	return output.String()
+}
@@ -0,0 +1,105 @@
+package enigma
+
+import (
+	"testing"
+
+	"github.com/marelinaa/cipher-algorithms/keys"
+)
+
+// cyrillic is the 33-letter alphabet of the custom machine
+const cyrillic = "АБВГДЕЁЖЗИЙКЛМНОПРСТУФХЦЧШЩЪЫЬЭЮЯ"
+
+func alphabetMap(alphabet string) map[rune]int {
+	m := make(map[rune]int)
+	for i, r := range []rune(alphabet) {
+		m[r] = i
+	}
+	return m
+}
+
+// affine returns the wiring that maps the i-th letter to the letter (a*i + b) mod power
+func affine(alphabet string, a, b int) string {
+	letters := []rune(alphabet)
+	wiring := make([]rune, len(letters))
+	for i := range letters {
+		wiring[i] = letters[(a*i+b)%len(letters)]
+	}
+	return string(wiring)
+}
+
+func newMachine(t *testing.T, key keys.Enigma, alphabet string) *Machine {
+	t.Helper()
+	m, err := New(key, alphabetMap(alphabet), len([]rune(alphabet)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestEncrypt(t *testing.T) {
+	tests := []struct {
+		name   string
+		key    keys.Enigma
+		input  string
+		output string
+	}{
+		{
+			"I-II-III B AAA",
+			keys.Enigma{Model: "I", Reflector: "B", Rotors: []string{"I", "II", "III"}, Rings: "AAA", Positions: "AAA"},
+			"AAAAA", "BDZGO",
+		},
+		{
+			"rings BBB",
+			keys.Enigma{Model: "I", Reflector: "B", Rotors: []string{"I", "II", "III"}, Rings: "BBB", Positions: "AAA"},
+			"AAAAA", "EWTYX",
+		},
+	}
+	for _, tt := range tests {
+		if got := newMachine(t, tt.key, Latin).Encrypt(tt.input); got != tt.output {
+			t.Errorf("%s: Encrypt(%q) = %q, want %q", tt.name, tt.input, got, tt.output)
+		}
+		// та же машина в исходном положении расшифровывает
+		if got := newMachine(t, tt.key, Latin).Encrypt(tt.output); got != tt.input {
+			t.Errorf("%s: Encrypt(%q) = %q, want %q", tt.name, tt.output, got, tt.input)
+		}
+	}
+}
+
+func TestDoubleStep(t *testing.T) {
+	key := keys.Enigma{Model: "I", Reflector: "B", Rotors: []string{"I", "II", "III"}, Rings: "AAA", Positions: "ADU"}
+	m := newMachine(t, key, Latin)
+
+	// средний ротор шагает вместе с левым на втором нажатии
+	want := []string{"ADV", "AEW", "BFX"}
+	for _, w := range want {
+		m.Encrypt("A")
+		if got := m.Positions(); got != w {
+			t.Fatalf("Positions() = %s, want %s", got, w)
+		}
+	}
+}
+
+func TestCustomCyrillic(t *testing.T) {
+	// отражатель меняет местами i-й и (32-i)-й символы, П отражается сам в себя
+	key := keys.Enigma{
+		Model:     Custom,
+		Reflector: affine(cyrillic, 32, 32),
+		Rotors: []string{
+			affine(cyrillic, 2, 5) + "/А",
+			affine(cyrillic, 4, 11) + "/Я",
+			affine(cyrillic, 5, 1) + "/ЖП",
+		},
+		Rings:     "БГЕ",
+		Positions: "ЯЮЬ",
+		Plugboard: "АЯБЮ",
+	}
+
+	plaintext := "ПРОВЕРКАШИФРАЭНИГМЫНАКИРИЛЛИЦЕИЗТРИДЦАТИТРЕХБУКВ"
+	ciphertext := newMachine(t, key, cyrillic).Encrypt(plaintext)
+	if ciphertext == plaintext {
+		t.Fatalf("Encrypt(%q) did not change the text", plaintext)
+	}
+	if got := newMachine(t, key, cyrillic).Encrypt(ciphertext); got != plaintext {
+		t.Errorf("Encrypt(%q) = %q, want %q", ciphertext, got, plaintext)
+	}
+}
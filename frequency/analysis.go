@@ -0,0 +1,73 @@
+package frequency
+
+import (
+	"math"
+
+	"github.com/marelinaa/cipher-algorithms/modmath"
+)
+
+// Count counts the symbols of the text that belong to the alphabet and returns the counts and their sum
+func Count(text string, alphabetMap map[rune]int) (map[rune]int, int) {
+	counts := make(map[rune]int)
+	total := 0
+	for _, char := range text {
+		if _, ok := alphabetMap[char]; ok {
+			counts[char]++
+			total++
+		}
+	}
+	return counts, total
+}
+
+// IndexOfCoincidence returns the probability that two symbols taken from the text at random are equal.
+// It is close to the sum of squared letter frequencies for a monoalphabetic cipher and to 1/power for a random text
+func IndexOfCoincidence(counts map[rune]int, total int) float64 {
+	if total < 2 {
+		return 0
+	}
+
+	sum := 0
+	for _, n := range counts {
+		sum += n * (n - 1)
+	}
+	return float64(sum) / float64(total*(total-1))
+}
+
+// ChiSquared compares the counts with the expected frequencies, the smaller the closer the text is to the language
+func ChiSquared(counts map[rune]int, total int, expected map[rune]float64) float64 {
+	chi := 0.0
+	for char, f := range expected {
+		e := f * float64(total)
+		if e == 0 {
+			continue
+		}
+		d := float64(counts[char]) - e
+		chi += d * d / e
+	}
+	return chi
+}
+
+// CaesarShift finds the Caesar key for which the decrypted text is closest to the language of the alphabet
+func CaesarShift(text string, alphabetMap map[rune]int, power int) int {
+	reverseAlphabetMap := make(map[int]rune)
+	for char, index := range alphabetMap {
+		reverseAlphabetMap[index] = char
+	}
+
+	counts, total := Count(text, alphabetMap)
+	expected := For(alphabetMap)
+
+	best, bestChi := 0, math.Inf(1)
+	for shift := 0; shift < power; shift++ {
+		// символ с индексом i расшифровывается в символ с индексом i - shift
+		shifted := make(map[rune]int)
+		for char, n := range counts {
+			shifted[reverseAlphabetMap[modmath.Mod(alphabetMap[char]-shift, power)]] = n
+		}
+
+		if chi := ChiSquared(shifted, total, expected); chi < bestChi {
+			best, bestChi = shift, chi
+		}
+	}
+	return best
+}
@@ -0,0 +1,64 @@
+package frequency
+
+import "unicode"
+
+// Russian contains relative frequencies (in percent) of letters and the space in Russian texts
+var Russian = map[rune]float64{
+	' ': 15.90, 'О': 9.28, 'Е': 7.14, 'А': 6.77, 'И': 6.18, 'Н': 5.63, 'Т': 5.26,
+	'С': 4.60, 'Р': 3.98, 'В': 3.82, 'Л': 3.70, 'К': 2.94, 'М': 2.70, 'Д': 2.51,
+	'П': 2.36, 'У': 2.20, 'Я': 1.69, 'Ы': 1.60, 'Ь': 1.46, 'Г': 1.43, 'З': 1.39,
+	'Б': 1.34, 'Ч': 1.21, 'Й': 1.02, 'Х': 0.82, 'Ж': 0.79, 'Ш': 0.61, 'Ю': 0.54,
+	'Ц': 0.40, 'Щ': 0.30, 'Э': 0.27, 'Ф': 0.22, 'Ъ': 0.03, 'Ё': 0.03,
+}
+
+// English contains relative frequencies (in percent) of letters and the space in English texts
+var English = map[rune]float64{
+	' ': 18.30, 'E': 10.38, 'T': 7.40, 'A': 6.68, 'O': 6.14, 'I': 5.70, 'N': 5.52,
+	'S': 5.17, 'H': 4.98, 'R': 4.89, 'D': 3.47, 'L': 3.29, 'C': 2.27, 'U': 2.26,
+	'M': 1.97, 'W': 1.93, 'F': 1.82, 'G': 1.65, 'Y': 1.61, 'P': 1.58, 'B': 1.05,
+	'V': 0.80, 'K': 0.63, 'J': 0.12, 'X': 0.12, 'Q': 0.08, 'Z': 0.06,
+}
+
+var tables = []map[rune]float64{Russian, English}
+
+// minimal frequency given to alphabet characters that are missing from the table
+const minimal = 0.01
+
+// For returns the frequencies of the alphabet characters normalized to sum up to 1.
+// The table covering most of the alphabet is used, letters are compared case-insensitively.
+// If no table covers the alphabet, all characters are equally frequent.
+func For(alphabetMap map[rune]int) map[rune]float64 {
+	var best map[rune]float64
+	bestCovered := 0
+	for _, table := range tables {
+		covered := 0
+		for char := range alphabetMap {
+			if _, ok := table[unicode.ToUpper(char)]; ok {
+				covered++
+			}
+		}
+		if covered > bestCovered {
+			best, bestCovered = table, covered
+		}
+	}
+
+	freqs := make(map[rune]float64)
+	sum := 0.0
+	for char := range alphabetMap {
+		f := 1.0
+		if best != nil {
+			f = best[unicode.ToUpper(char)]
+			if f < minimal {
+				f = minimal
+			}
+		}
+		freqs[char] = f
+		sum += f
+	}
+
+	for char := range freqs {
+		freqs[char] /= sum
+	}
+
+	return freqs
+}
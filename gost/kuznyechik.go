@@ -0,0 +1,173 @@
+package gost
+
+import (
+	"crypto/cipher"
+	"fmt"
+)
+
+// KuznyechikBlockSize is the Kuznyechik block size in bytes
+const KuznyechikBlockSize = 16
+
+// Pi is the nonlinear bijection of GOST R 34.12-2015, it is also used by the Streebog hash
+var Pi = [256]byte{
+	252, 238, 221, 17, 207, 110, 49, 22, 251, 196, 250, 218, 35, 197, 4, 77,
+	233, 119, 240, 219, 147, 46, 153, 186, 23, 54, 241, 187, 20, 205, 95, 193,
+	249, 24, 101, 90, 226, 92, 239, 33, 129, 28, 60, 66, 139, 1, 142, 79,
+	5, 132, 2, 174, 227, 106, 143, 160, 6, 11, 237, 152, 127, 212, 211, 31,
+	235, 52, 44, 81, 234, 200, 72, 171, 242, 42, 104, 162, 253, 58, 206, 204,
+	181, 112, 14, 86, 8, 12, 118, 18, 191, 114, 19, 71, 156, 183, 93, 135,
+	21, 161, 150, 41, 16, 123, 154, 199, 243, 145, 120, 111, 157, 158, 178, 177,
+	50, 117, 25, 61, 255, 53, 138, 126, 109, 84, 198, 128, 195, 189, 13, 87,
+	223, 245, 36, 169, 62, 168, 67, 201, 215, 121, 214, 246, 124, 34, 185, 3,
+	224, 15, 236, 222, 122, 148, 176, 188, 220, 232, 40, 80, 78, 51, 10, 74,
+	167, 151, 96, 115, 30, 0, 98, 68, 26, 184, 56, 130, 100, 159, 38, 65,
+	173, 69, 70, 146, 39, 94, 85, 47, 140, 163, 165, 125, 105, 213, 149, 59,
+	7, 88, 179, 64, 134, 172, 29, 247, 48, 55, 107, 228, 136, 217, 231, 137,
+	225, 27, 131, 73, 76, 63, 248, 254, 141, 83, 170, 144, 202, 216, 133, 97,
+	32, 113, 103, 164, 45, 43, 9, 91, 203, 155, 37, 208, 190, 229, 108, 82,
+	89, 166, 116, 210, 230, 244, 180, 192, 209, 102, 175, 194, 57, 75, 99, 182,
+}
+
+var piInverse [256]byte
+
+// lCoefficients are the coefficients of the linear function l, starting from the most significant byte
+var lCoefficients = [16]byte{148, 32, 133, 16, 194, 192, 1, 251, 1, 192, 194, 16, 133, 32, 148, 1}
+
+func init() {
+	for i, v := range Pi {
+		piInverse[v] = byte(i)
+	}
+}
+
+// gfMul multiplies in GF(2^8) modulo x^8 + x^7 + x^6 + x + 1
+func gfMul(a, b byte) byte {
+	var p byte
+	for b != 0 {
+		if b&1 != 0 {
+			p ^= a
+		}
+		carry := a & 0x80
+		a <<= 1
+		if carry != 0 {
+			a ^= 0xc3
+		}
+		b >>= 1
+	}
+	return p
+}
+
+type block [KuznyechikBlockSize]byte
+
+func (b *block) xor(k *block) {
+	for i := range b {
+		b[i] ^= k[i]
+	}
+}
+
+func (b *block) s() {
+	for i := range b {
+		b[i] = Pi[b[i]]
+	}
+}
+
+func (b *block) sInverse() {
+	for i := range b {
+		b[i] = piInverse[b[i]]
+	}
+}
+
+// l applies the linear transformation L, which is 16 rounds of the shift register R
+func (b *block) l() {
+	for round := 0; round < 16; round++ {
+		var x byte
+		for i, c := range lCoefficients {
+			x ^= gfMul(b[i], c)
+		}
+		copy(b[1:], b[:15])
+		b[0] = x
+	}
+}
+
+func (b *block) lInverse() {
+	for round := 0; round < 16; round++ {
+		first := b[0]
+		copy(b[:15], b[1:])
+		b[15] = first
+
+		var x byte
+		for i, c := range lCoefficients {
+			x ^= gfMul(b[i], c)
+		}
+		b[15] = x
+	}
+}
+
+// Kuznyechik is the 128-bit block cipher of GOST R 34.12-2015
+type Kuznyechik struct {
+	roundKeys [10]block
+}
+
+// NewKuznyechik creates the cipher with a 256-bit key
+func NewKuznyechik(key []byte) (cipher.Block, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("kuznyechik key must be %d bytes long, got %d", KeySize, len(key))
+	}
+
+	k := &Kuznyechik{}
+	copy(k.roundKeys[0][:], key[:16])
+	copy(k.roundKeys[1][:], key[16:])
+
+	// пары раундовых ключей получаются восемью раундами сети Фейстеля с константами C_i = L(i)
+	a1, a0 := k.roundKeys[0], k.roundKeys[1]
+	for i := 1; i <= 32; i++ {
+		var c block
+		c[15] = byte(i)
+		c.l()
+
+		t := a1
+		t.xor(&c)
+		t.s()
+		t.l()
+		t.xor(&a0)
+		a1, a0 = t, a1
+
+		if i%8 == 0 {
+			k.roundKeys[i/4] = a1
+			k.roundKeys[i/4+1] = a0
+		}
+	}
+
+	return k, nil
+}
+
+func (k *Kuznyechik) BlockSize() int {
+	return KuznyechikBlockSize
+}
+
+func (k *Kuznyechik) Encrypt(dst, src []byte) {
+	var b block
+	copy(b[:], src)
+
+	for i := 0; i < 9; i++ {
+		b.xor(&k.roundKeys[i])
+		b.s()
+		b.l()
+	}
+	b.xor(&k.roundKeys[9])
+
+	copy(dst, b[:])
+}
+
+func (k *Kuznyechik) Decrypt(dst, src []byte) {
+	var b block
+	copy(b[:], src)
+
+	b.xor(&k.roundKeys[9])
+	for i := 8; i >= 0; i-- {
+		b.lInverse()
+		b.sInverse()
+		b.xor(&k.roundKeys[i])
+	}
+
+	copy(dst, b[:])
+}
@@ -0,0 +1,36 @@
+package gost
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+// пример из приложения А.1 ГОСТ Р 34.12-2015
+func TestKuznyechikVector(t *testing.T) {
+	key, _ := hex.DecodeString("8899aabbccddeeff0011223344556677fedcba98765432100123456789abcdef")
+	plaintext, _ := hex.DecodeString("1122334455667700ffeeddccbbaa9988")
+	want, _ := hex.DecodeString("7f679d90bebc24305a468d42b9d4edcd")
+
+	block, err := NewKuznyechik(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := make([]byte, KuznyechikBlockSize)
+	block.Encrypt(got, plaintext)
+	if !bytes.Equal(got, want) {
+		t.Errorf("Encrypt = %x, want %x", got, want)
+	}
+
+	block.Decrypt(got, want)
+	if !bytes.Equal(got, plaintext) {
+		t.Errorf("Decrypt = %x, want %x", got, plaintext)
+	}
+}
+
+func TestKuznyechikKeySize(t *testing.T) {
+	if _, err := NewKuznyechik(make([]byte, KeySize+1)); err == nil {
+		t.Error("NewKuznyechik accepted a 33-byte key")
+	}
+}
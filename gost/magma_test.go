@@ -0,0 +1,36 @@
+package gost
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+// пример из приложения А.2 ГОСТ Р 34.12-2015
+func TestMagmaVector(t *testing.T) {
+	key, _ := hex.DecodeString("ffeeddccbbaa99887766554433221100f0f1f2f3f4f5f6f7f8f9fafbfcfdfeff")
+	plaintext, _ := hex.DecodeString("fedcba9876543210")
+	want, _ := hex.DecodeString("4ee901e5c2d8ca3d")
+
+	block, err := NewMagma(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := make([]byte, MagmaBlockSize)
+	block.Encrypt(got, plaintext)
+	if !bytes.Equal(got, want) {
+		t.Errorf("Encrypt = %x, want %x", got, want)
+	}
+
+	block.Decrypt(got, want)
+	if !bytes.Equal(got, plaintext) {
+		t.Errorf("Decrypt = %x, want %x", got, plaintext)
+	}
+}
+
+func TestMagmaKeySize(t *testing.T) {
+	if _, err := NewMagma(make([]byte, KeySize-1)); err == nil {
+		t.Error("NewMagma accepted a 31-byte key")
+	}
+}
@@ -0,0 +1,45 @@
+package gost
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+// сообщение M1 из приложения А ГОСТ Р 34.11-2012, байты записаны в порядке следования
+const streebogM1 = "012345678901234567890123456789012345678901234567890123456789012"
+
+func TestStreebogVectors(t *testing.T) {
+	tests := []struct {
+		name string
+		sum  func([]byte) []byte
+		want string
+	}{
+		{"256", Streebog256, "9d151eefd8590b89daa6ba6cb74af9275dd051026bb149a452fd84e5e57b5500"},
+		{"512", Streebog512, "1b54d01a4af5b9d5cc3d86d68d285462b19abc2475222f35c085122be4ba1ffa" +
+			"00ad30f8767b3a82384c6574f024c311e2a481332b08ef7f41797891c1646f48"},
+	}
+	for _, tt := range tests {
+		if got := hex.EncodeToString(tt.sum([]byte(streebogM1))); got != tt.want {
+			t.Errorf("Streebog%s(M1) = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+// хэш не зависит от того, какими частями записано сообщение
+func TestStreebogWrite(t *testing.T) {
+	message := make([]byte, 3*StreebogBlock+5)
+	for i := range message {
+		message[i] = byte(i)
+	}
+	want := hex.EncodeToString(Streebog512(message))
+
+	for _, chunk := range []int{1, 7, StreebogBlock, StreebogBlock + 1} {
+		d := NewStreebog512()
+		for i := 0; i < len(message); i += chunk {
+			d.Write(message[i:min(i+chunk, len(message))])
+		}
+		if got := hex.EncodeToString(d.Sum(nil)); got != want {
+			t.Errorf("written in chunks of %d: %s, want %s", chunk, got, want)
+		}
+	}
+}
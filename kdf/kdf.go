@@ -0,0 +1,98 @@
+// Package kdf derives keys from passphrases: PBKDF2 with HMAC-SHA256 turns the passphrase and the salt
+// into a seed, and Stream turns the seed into numbers that drive the choice of a classical key
+package kdf
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"hash"
+)
+
+const (
+	// DefaultIterations is the number of PBKDF2 iterations recommended for HMAC-SHA256
+	DefaultIterations = 600000
+	// MinSaltLength is the shortest salt accepted by the derive command, in bytes
+	MinSaltLength = 8
+	// SeedSize is the size of the seed made by Seed
+	SeedSize = sha256.Size
+)
+
+// PBKDF2 derives keyLen bytes from the password and the salt with HMAC-SHA256 (RFC 8018, section 5.2)
+func PBKDF2(password, salt []byte, iterations, keyLen int) []byte {
+	prf := hmac.New(sha256.New, password)
+	size := prf.Size()
+	blocks := (keyLen + size - 1) / size
+
+	dk := make([]byte, 0, blocks*size)
+	u := make([]byte, size)
+	for block := 1; block <= blocks; block++ {
+		// U1 = PRF(P, S || INT(i)), Uj = PRF(P, Uj-1), T = U1 xor U2 xor ... xor Uc
+		prf.Reset()
+		prf.Write(salt)
+		binary.Write(prf, binary.BigEndian, uint32(block))
+		u = prf.Sum(u[:0])
+		t := append([]byte{}, u...)
+
+		for j := 1; j < iterations; j++ {
+			prf.Reset()
+			prf.Write(u)
+			u = prf.Sum(u[:0])
+			for k := range t {
+				t[k] ^= u[k]
+			}
+		}
+		dk = append(dk, t...)
+	}
+	return dk[:keyLen]
+}
+
+// Seed derives the seed of a Stream from the passphrase and the salt
+func Seed(passphrase, salt string, iterations int) []byte {
+	return PBKDF2([]byte(passphrase), []byte(salt), iterations, SeedSize)
+}
+
+// Stream is a deterministic source of uniformly distributed numbers: the blocks are
+// HMAC-SHA256 of the label and a counter keyed with the seed. Different labels give
+// independent streams from one seed, so every cipher gets its own key from the same passphrase
+type Stream struct {
+	mac     hash.Hash
+	label   string
+	counter uint64
+	buf     []byte
+}
+
+// NewStream creates the stream of the seed and the label
+func NewStream(seed []byte, label string) *Stream {
+	return &Stream{mac: hmac.New(sha256.New, seed), label: label}
+}
+
+// Uint64 returns the next 8 bytes of the stream
+func (s *Stream) Uint64() uint64 {
+	if len(s.buf) < 8 {
+		s.mac.Reset()
+		s.mac.Write([]byte(s.label))
+		binary.Write(s.mac, binary.BigEndian, s.counter)
+		s.counter++
+		s.buf = s.mac.Sum(nil)
+	}
+	v := binary.BigEndian.Uint64(s.buf)
+	s.buf = s.buf[8:]
+	return v
+}
+
+// Intn returns a number in [0, n). Values below 2^64 mod n are skipped,
+// so every remainder is equally likely
+func (s *Stream) Intn(n int) (int, error) {
+	if n <= 0 {
+		return 0, fmt.Errorf("number of values must be positive, got %d", n)
+	}
+	m := uint64(n)
+	threshold := -m % m
+	for {
+		if v := s.Uint64(); v >= threshold {
+			return int(v % m), nil
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package kdf
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+// PBKDF2-HMAC-SHA256: примеры RFC 6070, пересчитанные для SHA-256, и пример из раздела 11 RFC 7914
+func TestPBKDF2Vectors(t *testing.T) {
+	tests := []struct {
+		password, salt string
+		iterations     int
+		want           string
+	}{
+		{"password", "salt", 1, "120fb6cffcf8b32c43e7225256c4f837a86548c92ccc35480805987cb70be17b"},
+		{"password", "salt", 2, "ae4d0c95af6b46d32d0adff928f06dd02a303f8ef3c251dfd6e2d85a95474c43"},
+		{"password", "salt", 4096, "c5e478d59288c841aa530db6845c4c8d962893a001ce4e11a4963873aa98134a"},
+		{"passwordPASSWORDpassword", "saltSALTsaltSALTsaltSALTsaltSALTsalt", 4096,
+			"348c89dbcbd32b2f32d814b8116e84cf2b17347ebc1800181c4e2a1fb8dd53e1c635518c7dac47e9"},
+		{"pass\x00word", "sa\x00lt", 4096, "89b69d0516f829893c696226650a8687"},
+		{"passwd", "salt", 1, "55ac046e56e3089fec1691c22544b605f94185216dde0465e68b9d57c20dacbc" +
+			"49ca9cccf179b645991664b39d77ef317c71b845b1e30bd509112041d3a19783"},
+	}
+	for _, tt := range tests {
+		got := hex.EncodeToString(PBKDF2([]byte(tt.password), []byte(tt.salt), tt.iterations, len(tt.want)/2))
+		if got != tt.want {
+			t.Errorf("PBKDF2(%q, %q, %d) = %s, want %s", tt.password, tt.salt, tt.iterations, got, tt.want)
+		}
+	}
+}
+
+func TestStreamIntn(t *testing.T) {
+	seed := Seed("correct horse", "saltsalt", 1000)
+
+	// один и тот же поток для одной метки, разные потоки для разных меток
+	a, b, c := NewStream(seed, "affine"), NewStream(seed, "affine"), NewStream(seed, "hill")
+	same, differ := true, false
+	for i := 0; i < 16; i++ {
+		x, err := a.Intn(1000)
+		if err != nil {
+			t.Fatal(err)
+		}
+		y, _ := b.Intn(1000)
+		z, _ := c.Intn(1000)
+		if x < 0 || x >= 1000 {
+			t.Fatalf("Intn(1000) = %d", x)
+		}
+		same = same && x == y
+		differ = differ || x != z
+	}
+	if !same {
+		t.Error("streams of one seed and label differ")
+	}
+	if !differ {
+		t.Error("streams of different labels are equal")
+	}
+
+	for _, n := range []int{0, -5} {
+		if _, err := a.Intn(n); err == nil {
+			t.Errorf("Intn(%d) returned no error", n)
+		}
+	}
+}
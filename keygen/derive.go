@@ -0,0 +1,145 @@
+package keygen
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/marelinaa/cipher-algorithms/modmath"
+)
+
+// Source gives uniformly distributed numbers in [0, n). Keys derived from a passphrase
+// use kdf.Stream, so the same passphrase and salt always give the same key
+type Source interface {
+	Intn(n int) (int, error)
+}
+
+// Deriver builds the key of a cipher from the source, length is the length of the keyword
+// for the ciphers whose key length is not fixed
+type Deriver func(src Source, length int, alphabetMap map[rune]int, power int) (string, error)
+
+// Derivers are the ciphers whose keys can be derived, by cipher ID
+var Derivers = map[string]Deriver{
+	"caesar":       DeriveCaesar,
+	"affine":       DeriveAffine,
+	"substitution": DeriveSubstitution,
+	"hill":         DeriveHill,
+	"permutation":  DerivePermutation,
+	"vigenere":     DeriveVigenere,
+}
+
+// DeriveCaesar chooses a non-zero shift, the shift 0 leaves the text unchanged
+func DeriveCaesar(src Source, length int, alphabetMap map[rune]int, power int) (string, error) {
+	if power < 2 {
+		return "", fmt.Errorf("alphabet must have at least 2 symbols")
+	}
+	shift, err := src.Intn(power - 1)
+	if err != nil {
+		return "", err
+	}
+	return string(orderedAlphabet(alphabetMap, power)[shift+1]), nil
+}
+
+// DeriveAffine chooses K1 coprime with the power and K2, the pair K1 = 1, K2 = 0 is skipped
+func DeriveAffine(src Source, length int, alphabetMap map[rune]int, power int) (string, error) {
+	if power < 2 {
+		return "", fmt.Errorf("alphabet must have at least 2 symbols")
+	}
+	alphabet := orderedAlphabet(alphabetMap, power)
+	units := modmath.Units(power)
+	for {
+		i, err := src.Intn(len(units))
+		if err != nil {
+			return "", err
+		}
+		k2, err := src.Intn(power)
+		if err != nil {
+			return "", err
+		}
+		if units[i] != 1 || k2 != 0 {
+			return string([]rune{alphabet[units[i]], alphabet[k2]}), nil
+		}
+	}
+}
+
+// DeriveSubstitution shuffles the alphabet with the Fisher-Yates algorithm
+func DeriveSubstitution(src Source, length int, alphabetMap map[rune]int, power int) (string, error) {
+	alphabet := orderedAlphabet(alphabetMap, power)
+	if err := shuffle(src, alphabet); err != nil {
+		return "", err
+	}
+	return string(alphabet), nil
+}
+
+// DeriveHill chooses matrices until the determinant is coprime with the power
+func DeriveHill(src Source, length int, alphabetMap map[rune]int, power int) (string, error) {
+	if power < 2 {
+		return "", fmt.Errorf("alphabet must have at least 2 symbols")
+	}
+	alphabet := orderedAlphabet(alphabetMap, power)
+	for {
+		var m [4]int
+		for i := range m {
+			var err error
+			m[i], err = src.Intn(power)
+			if err != nil {
+				return "", err
+			}
+		}
+
+		// единичная матрица не меняет текст
+		det := m[0]*m[3] - m[1]*m[2]
+		if det != 0 && modmath.Coprime(det, power) && m != [4]int{1, 0, 0, 1} {
+			return string([]rune{alphabet[m[0]], alphabet[m[1]], alphabet[m[2]], alphabet[m[3]]}), nil
+		}
+	}
+}
+
+// DerivePermutation chooses length different symbols, their order must not be sorted:
+// a keyword in alphabetical order leaves the columns in place
+func DerivePermutation(src Source, length int, alphabetMap map[rune]int, power int) (string, error) {
+	if length < 2 || length > power {
+		return "", fmt.Errorf("permutation keyword length must be from 2 to %d", power)
+	}
+	alphabet := orderedAlphabet(alphabetMap, power)
+	for {
+		if err := shuffle(src, alphabet); err != nil {
+			return "", err
+		}
+		keyword := alphabet[:length]
+		sorted := sort.SliceIsSorted(keyword, func(i, j int) bool {
+			return alphabetMap[keyword[i]] < alphabetMap[keyword[j]]
+		})
+		if !sorted {
+			return string(keyword), nil
+		}
+	}
+}
+
+// DeriveVigenere chooses length symbols
+func DeriveVigenere(src Source, length int, alphabetMap map[rune]int, power int) (string, error) {
+	if length < 1 {
+		return "", fmt.Errorf("vigenere key length must be at least 1")
+	}
+	alphabet := orderedAlphabet(alphabetMap, power)
+	key := make([]rune, length)
+	for i := range key {
+		idx, err := src.Intn(power)
+		if err != nil {
+			return "", err
+		}
+		key[i] = alphabet[idx]
+	}
+	return string(key), nil
+}
+
+// shuffle permutes the symbols with the Fisher-Yates algorithm driven by the source
+func shuffle(src Source, s []rune) error {
+	for i := len(s) - 1; i > 0; i-- {
+		j, err := src.Intn(i + 1)
+		if err != nil {
+			return err
+		}
+		s[i], s[j] = s[j], s[i]
+	}
+	return nil
+}
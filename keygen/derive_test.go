@@ -0,0 +1,43 @@
+package keygen
+
+import (
+	"testing"
+
+	"github.com/marelinaa/cipher-algorithms/kdf"
+)
+
+// ключи, выведенные из фразы, не должны меняться: иначе ключ из той же фразы и соли уже не восстановить
+func TestDeriveGolden(t *testing.T) {
+	const alphabet = "АБВГДЕЁЖЗИЙКЛМНОПРСТУФХЦЧШЩЪЫЬЭЮЯ "
+	alphabetMap := make(map[rune]int)
+	for i, char := range []rune(alphabet) {
+		alphabetMap[char] = i
+	}
+	power := len(alphabetMap)
+
+	golden := map[string]string{
+		"caesar":       "Я",
+		"affine":       "БЙ",
+		"substitution": "ВБЪПЯЭАЦЕСУКШЗ ДЙИЖМГФЬХЛЩЮЁРЧНОТЫ",
+		"hill":         "ИФТЙ",
+		"permutation":  "ЦБН ЙШЕЖ",
+		"vigenere":     "ЫАРТШЫУЬ",
+	}
+
+	seed := kdf.Seed("correct horse", "saltsalt", 1000)
+	for id, derive := range Derivers {
+		want, ok := golden[id]
+		if !ok {
+			t.Errorf("%s has no golden key", id)
+			continue
+		}
+		got, err := derive(kdf.NewStream(seed, id), 8, alphabetMap, power)
+		if err != nil {
+			t.Errorf("%s: %v", id, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("%s key = %q, want %q", id, got, want)
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package keygen
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+
+	"github.com/marelinaa/cipher-algorithms/frequency"
+	"github.com/marelinaa/cipher-algorithms/keys"
+	"github.com/marelinaa/cipher-algorithms/modmath"
+)
+
+// Intn returns a uniformly distributed random number in [0, n) from crypto/rand
+func Intn(n int) (int, error) {
+	i, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		return 0, err
+	}
+	return int(i.Int64()), nil
+}
+
+// Shuffle permutes the slice with the Fisher-Yates algorithm driven by crypto/rand
+func Shuffle(s []int) error {
+	for i := len(s) - 1; i > 0; i-- {
+		j, err := Intn(i + 1)
+		if err != nil {
+			return err
+		}
+		s[i], s[j] = s[j], s[i]
+	}
+	return nil
+}
+
+// Homophonic generates a table with total codes. Every character gets at least one code,
+// the rest are distributed proportionally to the language frequencies of the characters.
+func Homophonic(alphabetMap map[rune]int, power, total int) (keys.Homophonic, error) {
+	if total < power {
+		return keys.Homophonic{}, fmt.Errorf("number of codes must be at least the power of the alphabet (%d)", power)
+	}
+
+	freqs := frequency.For(alphabetMap)
+
+	chars := make([]rune, 0, power)
+	for char := range alphabetMap {
+		chars = append(chars, char)
+	}
+	sort.Slice(chars, func(i, j int) bool {
+		return alphabetMap[chars[i]] < alphabetMap[chars[j]]
+	})
+
+	// метод наибольших остатков: каждая буква получает целую часть своей доли, а оставшиеся
+	// коды достаются буквам с наибольшими дробными частями
+	counts := make(map[rune]int)
+	remainders := make(map[rune]float64)
+	spare := total - power
+	assigned := 0
+	for _, char := range chars {
+		share := freqs[char] * float64(spare)
+		counts[char] = 1 + int(math.Floor(share))
+		remainders[char] = share - math.Floor(share)
+		assigned += counts[char]
+	}
+
+	byRemainder := make([]rune, len(chars))
+	copy(byRemainder, chars)
+	sort.SliceStable(byRemainder, func(i, j int) bool {
+		return remainders[byRemainder[i]] > remainders[byRemainder[j]]
+	})
+	for i := 0; assigned < total; i++ {
+		counts[byRemainder[i%len(byRemainder)]]++
+		assigned++
+	}
+
+	codes := make([]int, total)
+	for i := range codes {
+		codes[i] = i
+	}
+	err := Shuffle(codes)
+	if err != nil {
+		return keys.Homophonic{}, err
+	}
+
+	key := keys.Homophonic{Codes: make(map[rune][]int)}
+	next := 0
+	for _, char := range chars {
+		homophones := codes[next : next+counts[char]]
+		sort.Ints(homophones)
+		key.Codes[char] = homophones
+		next += counts[char]
+	}
+
+	return key, nil
+}
+
+func orderedAlphabet(alphabetMap map[rune]int, power int) []rune {
+	alphabet := make([]rune, power)
+	for char, idx := range alphabetMap {
+		alphabet[idx] = char
+	}
+	return alphabet
+}
+
+// KeywordAlphabet builds a substitution alphabet from the keyword without repeated letters
+// followed by the rest of the alphabet. The result is rotated so that the keyword starts at position shift.
+func KeywordAlphabet(keyword string, shift int, alphabetMap map[rune]int, power int) (string, error) {
+	if keyword == "" {
+		return "", fmt.Errorf("keyword can not be empty")
+	}
+
+	mixed := make([]rune, 0, power)
+	seen := make(map[rune]bool)
+	for _, char := range keyword {
+		if _, ok := alphabetMap[char]; !ok {
+			return "", fmt.Errorf("keyword contains invalid symbol: '%c'", char)
+		}
+		if !seen[char] {
+			seen[char] = true
+			mixed = append(mixed, char)
+		}
+	}
+
+	for _, char := range orderedAlphabet(alphabetMap, power) {
+		if !seen[char] {
+			mixed = append(mixed, char)
+		}
+	}
+
+	shifted := make([]rune, power)
+	for i, char := range mixed {
+		shifted[modmath.Mod(i+shift, power)] = char
+	}
+
+	return string(shifted), nil
+}
+
+// Atbash returns the reversed alphabet
+func Atbash(alphabetMap map[rune]int, power int) string {
+	alphabet := orderedAlphabet(alphabetMap, power)
+	for i, j := 0, power-1; i < j; i, j = i+1, j-1 {
+		alphabet[i], alphabet[j] = alphabet[j], alphabet[i]
+	}
+	return string(alphabet)
+}
+
+// ROT returns the alphabet rotated by n positions, so every letter is replaced by the one n places further
+func ROT(n int, alphabetMap map[rune]int, power int) string {
+	alphabet := orderedAlphabet(alphabetMap, power)
+	rotated := make([]rune, power)
+	for i := range alphabet {
+		rotated[i] = alphabet[modmath.Mod(i+n, power)]
+	}
+	return string(rotated)
+}
+
+// Checkerboard builds a straddling checkerboard. The most frequent characters get the top row
+// in the order of the keyword alphabet, the rest fill the rows of the blank digits.
+func Checkerboard(keyword string, blanks []int, alphabetMap map[rune]int, power int) (keys.Checkerboard, error) {
+	needed := 1
+	if power > 10 {
+		needed = (power - 10 + 8) / 9 // в верхней строке 10-b букв, в остальных по 10
+	}
+	if needed > 9 {
+		return keys.Checkerboard{}, fmt.Errorf("alphabet of power %d does not fit into a checkerboard", power)
+	}
+	if len(blanks) != needed {
+		return keys.Checkerboard{}, fmt.Errorf("checkerboard for an alphabet of power %d needs %d blank digits", power, needed)
+	}
+	if power < 10-len(blanks) {
+		return keys.Checkerboard{}, fmt.Errorf("alphabet of power %d does not fill the %d cells of the top row", power, 10-len(blanks))
+	}
+
+	isBlank := make(map[int]bool)
+	for _, b := range blanks {
+		if b < 0 || b > 9 || isBlank[b] {
+			return keys.Checkerboard{}, fmt.Errorf("blank digits must be different digits")
+		}
+		isBlank[b] = true
+	}
+
+	mixed, err := KeywordAlphabet(keyword, 0, alphabetMap, power)
+	if err != nil {
+		return keys.Checkerboard{}, err
+	}
+
+	freqs := frequency.For(alphabetMap)
+	byFrequency := []rune(mixed)
+	sort.SliceStable(byFrequency, func(i, j int) bool {
+		return freqs[byFrequency[i]] > freqs[byFrequency[j]]
+	})
+	top := make(map[rune]bool)
+	for _, char := range byFrequency[:10-len(blanks)] {
+		top[char] = true
+	}
+
+	board := keys.Checkerboard{
+		Blanks: blanks,
+		Rows:   make([][10]rune, len(blanks)+1),
+	}
+
+	col := 0
+	var rest []rune
+	for _, char := range mixed {
+		if !top[char] {
+			rest = append(rest, char)
+			continue
+		}
+		for isBlank[col] {
+			col++
+		}
+		board.Rows[0][col] = char
+		col++
+	}
+
+	for i, char := range rest {
+		board.Rows[1+i/10][i%10] = char
+	}
+
+	return board, nil
+}
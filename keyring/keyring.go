@@ -0,0 +1,179 @@
+// Package keyring stores named keys in a JSON file. Every entry records the cipher and the alphabet
+// the key belongs to, so the key is not misread by another cipher as a line of key.txt can be
+package keyring
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/marelinaa/cipher-algorithms/keys"
+)
+
+// Version is the version of the file format written by Save
+const Version = 1
+
+var (
+	// ErrNotFound is returned when the keyring has no key with the name
+	ErrNotFound = errors.New("key not found")
+	// ErrExists is returned when a key with the name is already in the keyring
+	ErrExists = errors.New("key already exists")
+	// ErrFingerprint is returned for a key whose fingerprint does not match the recorded one
+	ErrFingerprint = errors.New("fingerprint does not match the key")
+)
+
+// Entry is a named key
+type Entry struct {
+	Name        string    `json:"name"`
+	Cipher      string    `json:"cipher"`
+	Alphabet    string    `json:"alphabet"`
+	Key         string    `json:"key"` // written as in key.txt
+	Created     time.Time `json:"created"`
+	Fingerprint string    `json:"fingerprint"` // keys.Fingerprint of the cipher, the alphabet and the key
+}
+
+// Check recomputes the fingerprint, so a key edited by hand is not used with its old fingerprint
+func (e Entry) Check() error {
+	if fp := keys.Fingerprint(e.Cipher, e.Alphabet, e.Key); fp != e.Fingerprint {
+		return fmt.Errorf("%w: key %q has fingerprint %s, the file records %s", ErrFingerprint, e.Name, fp, e.Fingerprint)
+	}
+	return nil
+}
+
+// Keyring is the content of the keyring file
+type Keyring struct {
+	Version int     `json:"version"`
+	Keys    []Entry `json:"keys"`
+}
+
+// Load reads the keyring file, a missing file is an empty keyring.
+// Keys with a wrong fingerprint are loaded too, so they can be listed and deleted, but Get refuses them
+func Load(path string) (*Keyring, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &Keyring{Version: Version}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var k Keyring
+	if err := json.Unmarshal(data, &k); err != nil {
+		return nil, fmt.Errorf("keyring %s: %v", path, err)
+	}
+	if k.Version < 1 || k.Version > Version {
+		return nil, fmt.Errorf("keyring %s: version %d is not supported, the latest is %d", path, k.Version, Version)
+	}
+	return &k, nil
+}
+
+// Save writes the keyring readable only by the owner, the old file is replaced at once
+// so an interrupted write does not lose the keys
+func (k *Keyring) Save(path string) error {
+	data, err := json.MarshalIndent(k, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(0600); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Add adds a key with the current time and its fingerprint and returns the stored entry
+func (k *Keyring) Add(name, cipher, alphabet, key string) (Entry, error) {
+	if name == "" || strings.TrimSpace(name) != name {
+		return Entry{}, fmt.Errorf("key name %q must be non-empty and have no leading or trailing spaces", name)
+	}
+	if key == "" {
+		return Entry{}, errors.New("key can not be empty")
+	}
+	if k.index(name) >= 0 {
+		return Entry{}, fmt.Errorf("%w: %s", ErrExists, name)
+	}
+
+	e := Entry{
+		Name:        name,
+		Cipher:      cipher,
+		Alphabet:    alphabet,
+		Key:         key,
+		Created:     time.Now().UTC().Truncate(time.Second),
+		Fingerprint: keys.Fingerprint(cipher, alphabet, key),
+	}
+	k.Keys = append(k.Keys, e)
+	return e, nil
+}
+
+func (k *Keyring) index(name string) int {
+	for i, e := range k.Keys {
+		if e.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// Get finds the key by its name, a key with a wrong fingerprint is not returned
+func (k *Keyring) Get(name string) (Entry, error) {
+	i := k.index(name)
+	if i < 0 {
+		return Entry{}, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	if err := k.Keys[i].Check(); err != nil {
+		return Entry{}, err
+	}
+	return k.Keys[i], nil
+}
+
+// ByFingerprint finds the key with the fingerprint, a key with a wrong fingerprint is not returned
+func (k *Keyring) ByFingerprint(fingerprint string) (Entry, error) {
+	for _, e := range k.Keys {
+		if e.Fingerprint == fingerprint {
+			if err := e.Check(); err != nil {
+				return Entry{}, err
+			}
+			return e, nil
+		}
+	}
+	return Entry{}, fmt.Errorf("%w: no key has fingerprint %s", ErrNotFound, fingerprint)
+}
+
+// Delete removes the key, also a key with a wrong fingerprint
+func (k *Keyring) Delete(name string) error {
+	i := k.index(name)
+	if i < 0 {
+		return fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	k.Keys = append(k.Keys[:i], k.Keys[i+1:]...)
+	return nil
+}
+
+// List returns the keys sorted by name, including keys with a wrong fingerprint
+func (k *Keyring) List() []Entry {
+	list := make([]Entry, len(k.Keys))
+	copy(list, k.Keys)
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name < list[j].Name
+	})
+	return list
+}
@@ -0,0 +1,54 @@
+package keyring
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestFingerprintMismatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keyring.json")
+	k, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := k.Add("good", "caesar", "АБВ", "Б"); err != nil {
+		t.Fatal(err)
+	}
+	bad, err := k.Add("bad", "caesar", "АБВ", "В")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// ключ исправлен вручную, отпечаток остался прежним
+	k.Keys[1].Key = "Б"
+	if err := k.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	k, err = Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if n := len(k.List()); n != 2 {
+		t.Errorf("List has %d keys, want 2", n)
+	}
+	if _, err := k.Get("good"); err != nil {
+		t.Errorf("Get(good): %v", err)
+	}
+	if _, err := k.Get("bad"); !errors.Is(err, ErrFingerprint) {
+		t.Errorf("Get(bad): err = %v, want ErrFingerprint", err)
+	}
+	if _, err := k.ByFingerprint(bad.Fingerprint); !errors.Is(err, ErrFingerprint) {
+		t.Errorf("ByFingerprint: err = %v, want ErrFingerprint", err)
+	}
+	if _, err := k.Add("bad", "caesar", "АБВ", "В"); !errors.Is(err, ErrExists) {
+		t.Errorf("Add over the bad key: err = %v, want ErrExists", err)
+	}
+
+	if err := k.Delete("bad"); err != nil {
+		t.Fatalf("Delete(bad): %v", err)
+	}
+	if _, err := k.Get("bad"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get after Delete: err = %v, want ErrNotFound", err)
+	}
+}
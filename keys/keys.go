@@ -1,6 +1,161 @@
 package keys
 
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Delimiter separates parts of a key that consists of several keywords
+const Delimiter = "|"
+
+// Fingerprint identifies the key: the first 8 bytes of SHA-256 over the cipher, the alphabet
+// and the key as written in key.txt, in hex. It hides only keys too many to try: the key of
+// a cipher with few keys is found by computing the fingerprint of every key
+func Fingerprint(cipher, alphabet, key string) string {
+	h := sha256.New()
+	for _, part := range []string{cipher, alphabet, key} {
+		// длина перед каждой частью, чтобы разные разбиения не давали одинаковый хэш
+		fmt.Fprintf(h, "%d:%s", len(part), part)
+	}
+	return hex.EncodeToString(h.Sum(nil)[:8])
+}
+
 type Affine struct {
 	K1 int
 	K2 int
 }
+
+type DoubleTransposition struct {
+	First  string
+	Second string
+}
+
+// Enigma describes the machine state, rotors are listed from left to right.
+// In key.txt it is written as model|reflector|rotors|rings|positions|plugboard, e.g. M3|B|I,II,III|AAA|AAA|ABCD
+type Enigma struct {
+	Model     string
+	Reflector string
+	Rotors    []string
+	Rings     string
+	Positions string
+	Plugboard string
+}
+
+// Homophonic maps every alphabet character to the numeric codes that can replace it.
+// In key.txt every character is written as char:code,code and entries are separated by Delimiter,
+// e.g. А:03,17|Б:08
+type Homophonic struct {
+	Codes map[rune][]int
+}
+
+// Width returns the number of digits every code takes in the ciphertext
+func (h Homophonic) Width() int {
+	maxCode := 0
+	for _, codes := range h.Codes {
+		for _, code := range codes {
+			if code > maxCode {
+				maxCode = code
+			}
+		}
+	}
+	return len(strconv.Itoa(maxCode))
+}
+
+// String formats the table for the key file
+func (h Homophonic) String() string {
+	chars := make([]rune, 0, len(h.Codes))
+	for char := range h.Codes {
+		chars = append(chars, char)
+	}
+	sort.Slice(chars, func(i, j int) bool {
+		return h.Codes[chars[i]][0] < h.Codes[chars[j]][0]
+	})
+
+	width := h.Width()
+	entries := make([]string, 0, len(chars))
+	for _, char := range chars {
+		codes := make([]string, 0, len(h.Codes[char]))
+		for _, code := range h.Codes[char] {
+			codes = append(codes, fmt.Sprintf("%0*d", width, code))
+		}
+		entries = append(entries, string(char)+":"+strings.Join(codes, ","))
+	}
+
+	return strings.Join(entries, Delimiter)
+}
+
+// Trithemius holds the initial shift and how much it grows after every letter.
+// In key.txt it is written as a symbol of the alphabet with an optional step, e.g. Б or Б|2
+type Trithemius struct {
+	Shift int
+	Step  int
+}
+
+// Alberti describes the cipher disk: the inner (movable) ring, the index letter on it
+// and the number of letters enciphered before the ring is turned.
+// In key.txt it is written as keyword|index|period, the keyword may also be the whole inner ring
+type Alberti struct {
+	Inner  []rune
+	Index  rune
+	Period int
+}
+
+// Quagmire holds the keyed plaintext and ciphertext alphabets and the indicator keyword.
+// In key.txt Quagmire I, II and III are written as keyword|indicator,
+// Quagmire IV as plainKeyword|cipherKeyword|indicator
+type Quagmire struct {
+	Plain     []rune
+	Cipher    []rune
+	Indicator string
+}
+
+// Checkerboard is a straddling checkerboard. Rows[0] is the top row, its Blanks cells hold 0.
+// Letters of the top row are encoded with their column digit, letters of Rows[i] with Blanks[i-1]
+// followed by the column digit
+type Checkerboard struct {
+	Blanks []int
+	Rows   [][10]rune
+}
+
+// VIC is the straddling checkerboard with optional chain addition and numeric transposition.
+// In key.txt it is written as keyword|blanks|seed|transposition, e.g. АСТРОНОМ|379|5187|31452,
+// the last two parts may be omitted and the seed may be left empty, e.g. АСТРОНОМ|379||31452
+type VIC struct {
+	Board         Checkerboard
+	Seed          string
+	Transposition string
+}
+
+// Pad points to a one-time pad file and the offset of the pad segment.
+// In key.txt it is written as path or path|offset, Offset is -1 when it is omitted
+type Pad struct {
+	Path   string
+	Offset int
+}
+
+// Block holds the key of a byte-oriented block cipher, the mode of operation and how the ciphertext
+// is written (hex or base64). In key.txt it is written as the key in hex optionally followed by the mode
+// and the encoding, e.g. 8899aabb...cdef|cbc|base64
+type Block struct {
+	Key      []byte
+	Mode     string
+	Encoding string
+}
+
+// LFSR holds the register kind (fibonacci or galois), the feedback polynomial exponents and the seed.
+// In key.txt it is written as kind|taps|seed with the seed in binary, e.g. fibonacci|16,14,13,11|1010110011100001
+type LFSR struct {
+	Kind string
+	Taps []int
+	Seed uint64
+}
+
+// A51 holds the 64-bit session key and the 22-bit frame number of A5/1, e.g. 1223456789abcdef|0x134
+type A51 struct {
+	Key   uint64
+	Frame uint32
+}
@@ -2,13 +2,19 @@ package main
 
 import (
 	"bufio"
+	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"log"
 	"os"
+	"strconv"
+	"strings"
 	"unicode/utf8"
 
-	"github.com/marelinaa/cipher-algorithms/decrypt"
-	"github.com/marelinaa/cipher-algorithms/encrypt"
+	"github.com/marelinaa/cipher-algorithms/registry"
+	"github.com/marelinaa/cipher-algorithms/trace"
+	"github.com/marelinaa/cipher-algorithms/tui"
 	"github.com/marelinaa/cipher-algorithms/verify"
 )
 
@@ -20,12 +26,16 @@ const (
 	keyFile         = "key.txt"
 	encryptFile     = "encrypt.txt"
 	decryptFile     = "decrypt.txt"
+	keyringFile     = "keyring.json"
 )
 
+var traceSteps = flag.Bool("trace", false, "show every step of the cipher as a table")
+
 var (
 	alphabetMap map[rune]int
 	power       int
 	input       string
+	textErr     error
 	result      string
 )
 
@@ -94,11 +104,8 @@ func initializeData() (string, error) {
 		return "", err
 	}
 
-	// check the text for accuracy
-	err = verify.Text(input, alphabetMap)
-	if err != nil {
-		return "", err
-	}
+	// check the text for accuracy, the error is reported once a cipher expecting alphabet text is chosen
+	textErr = verify.Text(input, alphabetMap)
 
 	// open the file with the key
 	keyString, err := openAndExtractText(keyFile)
@@ -116,142 +123,165 @@ func initializeData() (string, error) {
 func main() {
 	//fmt.Println()
 
+	flag.Parse()
+
+	// the steps are collected during one operation and printed after it
+	var steps *trace.Table
+	if *traceSteps {
+		steps = &trace.Table{}
+		trace.SetTracer(steps)
+	}
+
+	if flag.NArg() > 0 {
+		command, ok := commands[flag.Arg(0)]
+		if !ok {
+			log.Fatalf("unknown command: %s", flag.Arg(0))
+		}
+		err := command(flag.Args()[1:])
+		if steps != nil && len(steps.Steps) > 0 {
+			steps.Render(os.Stdout)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	// the interface runs on a terminal, the numbered menu is kept for piped input and for --trace
+	if !*traceSteps {
+		err := tui.Run(tuiConfig())
+		if err == nil {
+			return
+		}
+		if !errors.Is(err, tui.ErrNoTerminal) {
+			log.Fatal(err)
+		}
+	}
+
 	keyString, err := initializeData()
 	if err != nil {
 		log.Fatalf("error during initialization: %v", err)
 	}
+	menu(keyString, steps)
+}
+
+// tuiConfig reads the files for the terminal interface, a missing file leaves its editor empty
+func tuiConfig() tui.Config {
+	alphabet, err := openAndExtractText(alphabetFile)
+	if err != nil || alphabet == "" {
+		alphabet = defaultAlphabet
+	}
+	text, _ := openAndExtractText(textFile)
+	key, _ := openAndExtractText(keyFile)
+
+	return tui.Config{
+		Alphabet:    alphabet,
+		Key:         key,
+		Text:        text,
+		KeyFile:     keyFile,
+		EncryptFile: encryptFile,
+		DecryptFile: decryptFile,
+	}
+}
+
+// readChoice reads lines until one holds a number in the range from..to, it returns false when the input ends
+func readChoice(scanner *bufio.Scanner, from, to int, retry string) (int, bool) {
+	for scanner.Scan() {
+		choice, err := strconv.Atoi(strings.TrimSpace(scanner.Text()))
+		if err == nil && choice >= from && choice <= to {
+			return choice, true
+		}
+		fmt.Println(retry)
+	}
+	return 0, false
+}
+
+// menu is the numbered menu used when the program does not run in a terminal
+func menu(keyString string, steps *trace.Table) {
+	// signature schemes are used through the sign and verify commands
+	var ciphers []registry.Cipher
+	for _, c := range registry.All() {
+		if c.Encrypt != nil {
+			ciphers = append(ciphers, c)
+		}
+	}
+
+	scanner := bufio.NewScanner(os.Stdin)
+	var err error
 
 	// main logic
 	for {
 		// choosing a cryptosystem
 		fmt.Println("---------------------------")
 		fmt.Println("Choose the cryptographic system:")
-		fmt.Println("1: Caesar cipher")
-		fmt.Println("2: Affine cipher")
-		fmt.Println("3: Simple substitution cipher")
-		fmt.Println("4: Hill cipher")
-		fmt.Println("5: Permutation cipher")
-		fmt.Println("6: Vigenere cipher")
-		fmt.Println("0: Exit")
-
-		var cipherChoice int
-		for {
-			fmt.Scan(&cipherChoice)
-			if cipherChoice >= 0 && cipherChoice <= 6 {
-				break
-			}
-			fmt.Println("the wrong choice of cryptosystem, try again:")
+		for i, c := range ciphers {
+			fmt.Printf("%d: %s\n", i+1, c.Name)
 		}
+		fmt.Println("0: Exit")
 
-		if cipherChoice == 0 {
+		cipherChoice, ok := readChoice(scanner, 0, len(ciphers), "the wrong choice of cryptosystem, try again:")
+		if !ok || cipherChoice == 0 {
 			fmt.Println("Ending process")
 			break
 		}
+		c := ciphers[cipherChoice-1]
 
 		// Выбор операции
 		fmt.Println("Choose the operation:")
 		fmt.Println("1: Encryption")
 		fmt.Println("2: Decryption")
 
-		var operationChoice int
-		for {
-			fmt.Scan(&operationChoice)
-			if operationChoice == 1 || operationChoice == 2 {
-				break
-			}
-			fmt.Println("the wrong choice of operation, try again:")
+		operationChoice, ok := readChoice(scanner, 1, 2, "the wrong choice of operation, try again:")
+		if !ok {
+			fmt.Println("Ending process")
+			break
 		}
 
-		switch cipherChoice {
-		case 1:
-			// Caesar cipher
-			key, err := verify.CaesarKey(keyString, alphabetMap)
-			if err != nil {
-				log.Println(err)
-				continue
-			}
-			if operationChoice == 1 {
-				result = encrypt.Caesar(input, key, alphabetMap, power)
-				WriteToFile(encryptFile, result)
+		// only the input the cipher expects in the alphabet is checked against it
+		inputFormat := c.Plaintext
+		if operationChoice == 2 {
+			inputFormat = c.Ciphertext
+		}
+		if textErr != nil && inputFormat == registry.Alphabet {
+			log.Println(textErr)
+			continue
+		}
 
-			} else {
-				result = decrypt.Caesar(input, key, alphabetMap, power)
-				WriteToFile(decryptFile, result)
-			}
-		case 2:
-			key, err := verify.AffineKey(keyString, alphabetMap, power)
+		if c.NewKey != nil {
+			key, generated, err := c.NewKey(context.Background(), keyString, alphabetMap, power)
 			if err != nil {
 				log.Println(err)
 				continue
 			}
-			// Affine cipher
-			if operationChoice == 1 {
-				result = encrypt.Affine(input, key, alphabetMap, power)
-				WriteToFile(encryptFile, result)
-			} else {
-				result = decrypt.Affine(input, key, alphabetMap, power)
-				WriteToFile(decryptFile, result)
+			if generated {
+				keyString = key
+				WriteToFile(keyFile, keyString)
+				fmt.Printf("generated key saved to %s\n", keyFile)
 			}
-		case 3:
-			key, err := verify.SubstitutionKey(keyString, alphabetMap, power)
-			if err != nil {
-				log.Println(err)
-				continue
-			}
-			// Simple substitution
-			if operationChoice == 1 {
-				result = encrypt.Substitution(input, key, alphabetMap, power)
-				WriteToFile(encryptFile, result)
-			} else {
-				result = decrypt.Substitution(input, []rune(keyString), alphabetMap, power)
-				WriteToFile(decryptFile, result)
+		}
 
-			}
-		case 4:
-			key, err := verify.HillKey(keyString, alphabetMap, power)
-			if err != nil {
-				log.Println(err)
-				continue
-			}
-			// Hill cipher
-			if operationChoice == 1 {
-				result = encrypt.Hill(input, key, alphabetMap, power)
-				WriteToFile(encryptFile, result)
-			} else {
-				result = decrypt.Hill(input, key, alphabetMap, power)
-				WriteToFile(decryptFile, result)
-			}
-		case 5:
-			err := verify.PermutationKey(keyString, alphabetMap, power)
+		if steps != nil {
+			steps.Reset()
+		}
+
+		if operationChoice == 1 {
+			result, err = c.Encrypt(input, keyString, alphabetMap, power)
 			if err != nil {
 				log.Println(err)
 				continue
 			}
-			// Permutation cipher
-			if operationChoice == 1 {
-				result = encrypt.Permutation(input, keyString, alphabetMap, power)
-				WriteToFile(encryptFile, result)
-			} else {
-				result = decrypt.Permutation(input, keyString, alphabetMap)
-				WriteToFile(decryptFile, result)
-			}
-		case 6:
-			err := verify.VigenereKey(keyString, alphabetMap, power)
+			WriteToFile(encryptFile, result)
+		} else {
+			result, err = c.Decrypt(input, keyString, alphabetMap, power)
 			if err != nil {
 				log.Println(err)
 				continue
 			}
-			// Vigenere cipher
-			if operationChoice == 1 {
-				result = encrypt.Vigenere(input, keyString, alphabetMap)
-				WriteToFile(encryptFile, result)
-			} else {
-				result = decrypt.Vigenere(input, keyString, alphabetMap)
-				WriteToFile(decryptFile, result)
-			}
-		default:
-			log.Println("Wrong choise")
-			continue
+			WriteToFile(decryptFile, result)
+		}
+
+		if steps != nil {
+			steps.Render(os.Stdout)
 		}
 	}
 }
@@ -0,0 +1,191 @@
+package modmath
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+)
+
+// ErrNotInvertible is returned when a number has no inverse modulo n
+var ErrNotInvertible = errors.New("no modular inverse exists")
+
+// Mod returns x mod n in [0, n) for any sign of x, n must be positive
+func Mod(x, n int) int {
+	r := x % n
+	if r < 0 {
+		r += n
+	}
+	return r
+}
+
+// GCD returns the greatest common divisor of |a| and |b|
+func GCD(a, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	if a < 0 {
+		return -a
+	}
+	return a
+}
+
+// Coprime reports whether gcd(a, b) = 1
+func Coprime(a, b int) bool {
+	return GCD(a, b) == 1
+}
+
+// ExtendedGCD returns g = gcd(a, b) and the Bezout coefficients x, y with ax + by = g
+func ExtendedGCD(a, b int) (g, x, y int) {
+	// инвариант: a*x0 + b*y0 = r0, a*x1 + b*y1 = r1
+	r0, r1 := a, b
+	x0, x1 := 1, 0
+	y0, y1 := 0, 1
+	for r1 != 0 {
+		q := r0 / r1
+		r0, r1 = r1, r0-q*r1
+		x0, x1 = x1, x0-q*x1
+		y0, y1 = y1, y0-q*y1
+	}
+
+	if r0 < 0 {
+		return -r0, -x0, -y0
+	}
+	return r0, x0, y0
+}
+
+// Inverse returns a^-1 mod n found by the extended Euclidean algorithm
+func Inverse(a, n int) (int, error) {
+	if n <= 0 {
+		return 0, fmt.Errorf("modulus must be positive, got %d", n)
+	}
+
+	g, x, _ := ExtendedGCD(Mod(a, n), n)
+	if g != 1 {
+		return 0, fmt.Errorf("%w: gcd(%d, %d) = %d", ErrNotInvertible, a, n, g)
+	}
+	return Mod(x, n), nil
+}
+
+// Det returns the determinant of the 2x2 matrix modulo n
+func Det(m [2][2]int, n int) int {
+	return Mod(m[0][0]*m[1][1]-m[0][1]*m[1][0], n)
+}
+
+// InverseMatrix returns the inverse of the 2x2 matrix modulo n: det^-1 * [[m11, -m01], [-m10, m00]].
+// The error wraps ErrNotInvertible when the determinant is not coprime with n
+func InverseMatrix(m [2][2]int, n int) ([2][2]int, error) {
+	detInverse, err := Inverse(Det(m, n), n)
+	if err != nil {
+		return [2][2]int{}, err
+	}
+	return [2][2]int{
+		{Mod(m[1][1]*detInverse, n), Mod(-m[0][1]*detInverse, n)},
+		{Mod(-m[1][0]*detInverse, n), Mod(m[0][0]*detInverse, n)},
+	}, nil
+}
+
+// MulVector returns the row vector (v0, v1) multiplied by the 2x2 matrix modulo n
+func MulVector(v0, v1 int, m [2][2]int, n int) (int, int) {
+	return Mod(v0*m[0][0]+v1*m[1][0], n), Mod(v0*m[0][1]+v1*m[1][1], n)
+}
+
+// MulMod returns a*b mod n without overflow
+func MulMod(a, b, n int) int {
+	hi, lo := bits.Mul64(uint64(Mod(a, n)), uint64(Mod(b, n)))
+	return int(bits.Rem64(hi, lo, uint64(n)))
+}
+
+// Exp returns base^exp mod n by square-and-multiply, exp must not be negative
+func Exp(base, exp, n int) int {
+	if n == 1 {
+		return 0
+	}
+
+	result := 1
+	base = Mod(base, n)
+	for ; exp > 0; exp >>= 1 {
+		if exp&1 == 1 {
+			result = MulMod(result, base, n)
+		}
+		base = MulMod(base, base, n)
+	}
+	return result
+}
+
+// Factor returns the prime divisors of n > 1 in increasing order and their exponents
+func Factor(n int) (primes, exponents []int) {
+	for p := 2; p*p <= n; p++ {
+		if n%p != 0 {
+			continue
+		}
+		k := 0
+		for n%p == 0 {
+			n /= p
+			k++
+		}
+		primes, exponents = append(primes, p), append(exponents, k)
+	}
+	if n > 1 {
+		primes, exponents = append(primes, n), append(exponents, 1)
+	}
+	return primes, exponents
+}
+
+// Totient returns Euler's function phi(n), the number of units modulo n
+func Totient(n int) int {
+	if n <= 0 {
+		return 0
+	}
+
+	// phi(n) = n * prod(1 - 1/p) по простым делителям p
+	result := n
+	primes, _ := Factor(n)
+	for _, p := range primes {
+		result -= result / p
+	}
+	return result
+}
+
+// Units lists the numbers in [1, n) coprime with n, the elements of the multiplicative group modulo n
+func Units(n int) []int {
+	var units []int
+	for a := 1; a < n; a++ {
+		if Coprime(a, n) {
+			units = append(units, a)
+		}
+	}
+	if n == 1 {
+		units = []int{0}
+	}
+	return units
+}
+
+// CRT solves x = remainders[i] mod moduli[i] with the Chinese remainder theorem. The moduli do not have
+// to be coprime: the result is x modulo their least common multiple, or an error when the system has no solution
+func CRT(remainders, moduli []int) (x, m int, err error) {
+	if len(remainders) != len(moduli) || len(moduli) == 0 {
+		return 0, 0, errors.New("crt needs as many remainders as moduli, at least one")
+	}
+
+	x, m = 0, 1
+	for i, n := range moduli {
+		if n <= 0 {
+			return 0, 0, fmt.Errorf("modulus must be positive, got %d", n)
+		}
+		r := Mod(remainders[i], n)
+
+		// x + m*t = r (mod n)  =>  m*t = r - x (mod n)
+		g, inv, _ := ExtendedGCD(m, n)
+		if (r-x)%g != 0 {
+			return 0, 0, fmt.Errorf("congruences x = %d mod %d and x = %d mod %d are incompatible", x, m, r, n)
+		}
+
+		step := n / g
+		t := MulMod((r-x)/g, inv, step)
+		x += m * t
+		m *= step
+		x = Mod(x, m)
+	}
+
+	return x, m, nil
+}
@@ -0,0 +1,139 @@
+package modmath
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestMod(t *testing.T) {
+	tests := []struct{ x, n, want int }{
+		{7, 5, 2},
+		{0, 5, 0},
+		{-1, 5, 4},
+		{-7, 5, 3},
+		// ровно кратное отрицательное число давало n вместо 0
+		{-5, 5, 0},
+		{-34, 34, 0},
+		{-68, 34, 0},
+		{5, 1, 0},
+	}
+	for _, tt := range tests {
+		if got := Mod(tt.x, tt.n); got != tt.want {
+			t.Errorf("Mod(%d, %d) = %d, want %d", tt.x, tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestInverse(t *testing.T) {
+	tests := []struct{ a, n, want int }{
+		{3, 7, 5},
+		{-3, 7, 2},
+		{5, 34, 7},
+		{33, 34, 33},
+		{1, 1, 0},
+	}
+	for _, tt := range tests {
+		got, err := Inverse(tt.a, tt.n)
+		if err != nil || got != tt.want {
+			t.Errorf("Inverse(%d, %d) = %d, %v, want %d", tt.a, tt.n, got, err, tt.want)
+		}
+	}
+
+	for _, tt := range []struct{ a, n int }{{2, 34}, {0, 7}, {17, 34}} {
+		if _, err := Inverse(tt.a, tt.n); !errors.Is(err, ErrNotInvertible) {
+			t.Errorf("Inverse(%d, %d): err = %v, want ErrNotInvertible", tt.a, tt.n, err)
+		}
+	}
+	if _, err := Inverse(3, 0); err == nil || errors.Is(err, ErrNotInvertible) {
+		t.Errorf("Inverse(3, 0): err = %v, want an error about the modulus", err)
+	}
+}
+
+func TestInverseMatrix(t *testing.T) {
+	m := [2][2]int{{3, 4}, {5, 9}}
+	inverse, err := InverseMatrix(m, 34)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range [][2]int{{0, 0}, {1, 2}, {33, 17}, {20, 5}} {
+		c0, c1 := MulVector(v[0], v[1], m, 34)
+		p0, p1 := MulVector(c0, c1, inverse, 34)
+		if p0 != v[0] || p1 != v[1] {
+			t.Errorf("(%d, %d) * M * M^-1 = (%d, %d)", v[0], v[1], p0, p1)
+		}
+	}
+
+	if _, err := InverseMatrix([2][2]int{{2, 4}, {1, 3}}, 34); !errors.Is(err, ErrNotInvertible) {
+		t.Errorf("matrix with determinant 2: err = %v, want ErrNotInvertible", err)
+	}
+}
+
+func TestCRT(t *testing.T) {
+	tests := []struct {
+		remainders, moduli []int
+		x, m               int
+	}{
+		{[]int{2, 3, 2}, []int{3, 5, 7}, 23, 105},
+		{[]int{-1, 0}, []int{4, 3}, 3, 12},
+		// модули не взаимно просты, но сравнения совместны
+		{[]int{3, 5}, []int{4, 6}, 11, 12},
+		{[]int{7}, []int{10}, 7, 10},
+	}
+	for _, tt := range tests {
+		x, m, err := CRT(tt.remainders, tt.moduli)
+		if err != nil || x != tt.x || m != tt.m {
+			t.Errorf("CRT(%v, %v) = %d, %d, %v, want %d, %d", tt.remainders, tt.moduli, x, m, err, tt.x, tt.m)
+		}
+	}
+
+	for _, tt := range []struct{ remainders, moduli []int }{
+		{[]int{1, 2}, []int{4, 6}},
+		{[]int{1}, []int{0}},
+		{[]int{1, 2}, []int{3}},
+		{nil, nil},
+	} {
+		if _, _, err := CRT(tt.remainders, tt.moduli); err == nil {
+			t.Errorf("CRT(%v, %v) returned no error", tt.remainders, tt.moduli)
+		}
+	}
+}
+
+func TestTotient(t *testing.T) {
+	tests := []struct{ n, want int }{
+		{1, 1},
+		{2, 1},
+		{7, 6},
+		{26, 12},
+		{33, 20},
+		{34, 16},
+		{36, 12},
+		{0, 0},
+		{-4, 0},
+	}
+	for _, tt := range tests {
+		if got := Totient(tt.n); got != tt.want {
+			t.Errorf("Totient(%d) = %d, want %d", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestUnits(t *testing.T) {
+	tests := []struct {
+		n    int
+		want []int
+	}{
+		{1, []int{0}},
+		{2, []int{1}},
+		{9, []int{1, 2, 4, 5, 7, 8}},
+		{12, []int{1, 5, 7, 11}},
+	}
+	for _, tt := range tests {
+		if got := Units(tt.n); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Units(%d) = %v, want %v", tt.n, got, tt.want)
+		}
+	}
+	if got := len(Units(34)); got != Totient(34) {
+		t.Errorf("len(Units(34)) = %d, want phi(34) = %d", got, Totient(34))
+	}
+}
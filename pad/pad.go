@@ -0,0 +1,181 @@
+package pad
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/marelinaa/cipher-algorithms/keygen"
+)
+
+// Kind tells what a pad consists of
+type Kind string
+
+const (
+	Alphabet Kind = "alphabet" // symbols of the alphabet
+	Bytes    Kind = "bytes"    // bytes written in hexadecimal
+)
+
+// Pad is a one-time pad file. The first line holds the kind of the pad and the number of
+// pad symbols (or bytes) already consumed, the second line holds the pad itself
+type Pad struct {
+	Kind   Kind
+	Offset int
+	Data   string
+}
+
+// Len returns the number of pad symbols (or bytes)
+func (p Pad) Len() int {
+	if p.Kind == Bytes {
+		return len(p.Data) / 2
+	}
+	return utf8.RuneCountInString(p.Data)
+}
+
+// Read loads the pad file
+func Read(path string) (Pad, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return Pad{}, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1<<30)
+
+	var lines []string
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return Pad{}, err
+	}
+
+	if len(lines) != 2 {
+		return Pad{}, fmt.Errorf("pad file %s must contain a header and the pad", path)
+	}
+
+	header := strings.Fields(lines[0])
+	if len(header) != 2 || (Kind(header[0]) != Alphabet && Kind(header[0]) != Bytes) {
+		return Pad{}, fmt.Errorf("pad file %s has invalid header %q", path, lines[0])
+	}
+
+	offset, err := strconv.Atoi(header[1])
+	if err != nil || offset < 0 {
+		return Pad{}, fmt.Errorf("pad file %s has invalid offset %q", path, header[1])
+	}
+
+	p := Pad{
+		Kind:   Kind(header[0]),
+		Offset: offset,
+		Data:   lines[1],
+	}
+
+	if p.Kind == Bytes {
+		if _, err := hex.DecodeString(p.Data); err != nil {
+			return Pad{}, fmt.Errorf("pad file %s contains invalid hex: %v", path, err)
+		}
+	}
+	if p.Offset > p.Len() {
+		return Pad{}, fmt.Errorf("pad file %s offset is past the end of the pad", path)
+	}
+
+	return p, nil
+}
+
+// Write saves the pad file
+func Write(path string, p Pad) error {
+	return os.WriteFile(path, []byte(fmt.Sprintf("%s %d\n%s\n", p.Kind, p.Offset, p.Data)), 0600)
+}
+
+// GenerateAlphabet creates a pad file of n alphabet symbols chosen with crypto/rand
+func GenerateAlphabet(path string, n int, alphabetMap map[rune]int, power int) error {
+	reverseAlphabetMap := make(map[int]rune)
+	for char, index := range alphabetMap {
+		reverseAlphabetMap[index] = char
+	}
+
+	var data strings.Builder
+	for i := 0; i < n; i++ {
+		idx, err := keygen.Intn(power)
+		if err != nil {
+			return err
+		}
+		data.WriteRune(reverseAlphabetMap[idx])
+	}
+
+	return Write(path, Pad{Kind: Alphabet, Data: data.String()})
+}
+
+// GenerateBytes creates a pad file of n random bytes from crypto/rand
+func GenerateBytes(path string, n int) error {
+	data := make([]byte, n)
+	_, err := rand.Read(data)
+	if err != nil {
+		return err
+	}
+
+	return Write(path, Pad{Kind: Bytes, Data: hex.EncodeToString(data)})
+}
+
+// segment returns n pad symbols (or bytes for byte pads, as hex) starting at start
+func (p Pad) segment(start, n int) (string, error) {
+	if start+n > p.Len() {
+		return "", fmt.Errorf("pad is too short: %d symbols left after offset %d, %d needed", p.Len()-start, start, n)
+	}
+
+	if p.Kind == Bytes {
+		return p.Data[2*start : 2*(start+n)], nil
+	}
+	return string([]rune(p.Data)[start : start+n]), nil
+}
+
+// Take consumes n symbols of the pad and records the new offset in the pad file.
+// A negative start takes the next unused segment, a start inside the used part is refused.
+func Take(path string, kind Kind, start, n int) (int, string, error) {
+	p, err := Read(path)
+	if err != nil {
+		return 0, "", err
+	}
+	if p.Kind != kind {
+		return 0, "", fmt.Errorf("pad %s holds %s, not %s", path, p.Kind, kind)
+	}
+
+	if start < 0 {
+		start = p.Offset
+	}
+	if start < p.Offset {
+		return 0, "", fmt.Errorf("pad segment starting at %d is already used, the pad can be used from offset %d", start, p.Offset)
+	}
+
+	segment, err := p.segment(start, n)
+	if err != nil {
+		return 0, "", err
+	}
+
+	p.Offset = start + n
+	err = Write(path, p)
+	if err != nil {
+		return 0, "", err
+	}
+
+	return start, segment, nil
+}
+
+// Segment returns n symbols of the pad starting at start without consuming them, for decryption
+func Segment(path string, kind Kind, start, n int) (string, error) {
+	p, err := Read(path)
+	if err != nil {
+		return "", err
+	}
+	if p.Kind != kind {
+		return "", fmt.Errorf("pad %s holds %s, not %s", path, p.Kind, kind)
+	}
+
+	return p.segment(start, n)
+}
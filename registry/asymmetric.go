@@ -0,0 +1,381 @@
+package registry
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/marelinaa/cipher-algorithms/asymmetric"
+	"github.com/marelinaa/cipher-algorithms/asymmetric/elgamal"
+	"github.com/marelinaa/cipher-algorithms/asymmetric/rsa"
+	"github.com/marelinaa/cipher-algorithms/asymmetric/shamir"
+	"github.com/marelinaa/cipher-algorithms/ecc"
+	"github.com/marelinaa/cipher-algorithms/keys"
+	"github.com/marelinaa/cipher-algorithms/trace"
+	"github.com/marelinaa/cipher-algorithms/verify"
+)
+
+// maxKeyBits limits generated keys, larger primes take too long to find
+const maxKeyBits = 4096
+
+// newKeyOfSize calls generate when the key is written as new|bits
+func newKeyOfSize(params, name string, generate func(bits int) ([]*big.Int, error)) (string, bool, error) {
+	parts := strings.Split(params, keys.Delimiter)
+	if parts[0] != "new" {
+		return "", false, nil
+	}
+	if len(parts) != 2 {
+		return "", false, fmt.Errorf("%s generation key must look like new%sbits", name, keys.Delimiter)
+	}
+
+	bits, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return "", false, fmt.Errorf("%s key size must be a number: %q", name, parts[1])
+	}
+
+	if bits > maxKeyBits {
+		return "", false, fmt.Errorf("%s key size must be at most %d bits", name, maxKeyBits)
+	}
+
+	numbers, err := generate(bits)
+	if err != nil {
+		return "", false, err
+	}
+	return joinNumbers(numbers...), true, nil
+}
+
+// KeyBits returns the key size asked by params written as new|bits, it returns false for other params
+func KeyBits(params string) (int, bool) {
+	parts := strings.Split(params, keys.Delimiter)
+	if len(parts) != 2 || parts[0] != "new" {
+		return 0, false
+	}
+	bits, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, false
+	}
+	return bits, true
+}
+
+// numberBits returns the size in bits of the largest number written in the key, parts that are not
+// numbers are skipped. Numbers longer than maxDigits are not parsed, their size is estimated from the digits
+func numberBits(key string) int {
+	const maxDigits = 4 * maxKeyBits
+	size := 0
+	for _, part := range strings.Split(key, keys.Delimiter) {
+		digits := strings.TrimLeft(part, "0")
+		if len(digits) > maxDigits {
+			size = max(size, int(float64(len(digits))*math.Log2(10)))
+			continue
+		}
+		if n, ok := new(big.Int).SetString(part, 10); ok {
+			size = max(size, n.BitLen())
+		}
+	}
+	return size
+}
+
+// joinNumbers writes the key parts in decimal separated by the key delimiter
+func joinNumbers(numbers ...*big.Int) string {
+	parts := make([]string, len(numbers))
+	for i, n := range numbers {
+		parts[i] = n.String()
+	}
+	return strings.Join(parts, keys.Delimiter)
+}
+
+// traceValue reports an intermediate value of a protocol to the tracer, pos is the number of the block
+// or 0 for the values of the key. The values include secrets, so they are shown only when tracing is on
+func traceValue(pos int, name, formula string, value any) {
+	if trace.Enabled() {
+		trace.Emit(trace.Step{Pos: pos, Input: name, Formula: formula, Output: fmt.Sprint(value)})
+	}
+}
+
+// newRSA generates an RSA key and traces its parts for hand calculations
+func newRSA(ctx context.Context, params string, alphabetMap map[rune]int, power int) (string, bool, error) {
+	return newKeyOfSize(params, "rsa", func(bits int) ([]*big.Int, error) {
+		key, err := rsa.GenerateKey(ctx, bits)
+		if err != nil {
+			return nil, err
+		}
+		traceValue(0, "p", "random prime", key.P)
+		traceValue(0, "q", "random prime", key.Q)
+		traceValue(0, "n", "p*q", key.N)
+		traceValue(0, "phi(n)", "(p-1)*(q-1)", key.Phi)
+		traceValue(0, "e", "coprime with phi(n)", key.E)
+		traceValue(0, "d", "e^-1 mod phi(n)", key.D)
+		return []*big.Int{key.N, key.E, key.D}, nil
+	})
+}
+
+// groups splits the numbers into groups of the given size
+func groups(input string, size int) ([][]*big.Int, error) {
+	numbers, err := asymmetric.ParseNumbers(input)
+	if err != nil {
+		return nil, err
+	}
+	if len(numbers)%size != 0 {
+		return nil, fmt.Errorf("ciphertext must consist of groups of %d numbers", size)
+	}
+
+	var result [][]*big.Int
+	for i := 0; i < len(numbers); i += size {
+		result = append(result, numbers[i:i+size])
+	}
+	return result, nil
+}
+
+func elgamalCipher() Cipher {
+	return Cipher{
+		ID:         "elgamal",
+		Name:       "ElGamal (alphabet text in blocks)",
+		KeyFormat:  "p|g|y|x in decimal, p|g|y to encrypt only, or new|bits to generate a group and a key",
+		KeySize:    numberBits,
+		Ciphertext: Digits,
+		Encrypt: func(input, keyString string, alphabetMap map[rune]int, power int) (string, error) {
+			key, err := verify.ElGamalKey(keyString)
+			if err != nil {
+				return "", err
+			}
+			blocks, err := asymmetric.EncodeText(input, alphabetMap, power, key.P)
+			if err != nil {
+				return "", err
+			}
+
+			var ciphertext []*big.Int
+			for i, m := range blocks {
+				e, err := elgamal.Encrypt(&key.PublicKey, m)
+				if err != nil {
+					return "", err
+				}
+				traceValue(i+1, "m", "block of the text", m)
+				traceValue(i+1, "k", "random ephemeral exponent", e.K)
+				traceValue(i+1, "a", "g^k mod p", e.A)
+				traceValue(i+1, "b", "y^k*m mod p", e.B)
+				ciphertext = append(ciphertext, e.A, e.B)
+			}
+			return asymmetric.FormatNumbers(ciphertext), nil
+		},
+		Decrypt: func(input, keyString string, alphabetMap map[rune]int, power int) (string, error) {
+			key, err := verify.ElGamalKey(keyString)
+			if err != nil {
+				return "", err
+			}
+			pairs, err := groups(input, 2)
+			if err != nil {
+				return "", err
+			}
+
+			var blocks []*big.Int
+			for i, pair := range pairs {
+				d, err := elgamal.Decrypt(key, pair[0], pair[1])
+				if err != nil {
+					return "", err
+				}
+				traceValue(i+1, "s", "a^x mod p", d.S)
+				traceValue(i+1, "s^-1", "s^-1 mod p", d.SInverse)
+				traceValue(i+1, "m", "b*s^-1 mod p", d.M)
+				blocks = append(blocks, d.M)
+			}
+			return asymmetric.DecodeText(blocks, alphabetMap, power)
+		},
+		NewKey: func(ctx context.Context, params string, alphabetMap map[rune]int, power int) (string, bool, error) {
+			return newKeyOfSize(params, "elgamal", func(bits int) ([]*big.Int, error) {
+				key, err := elgamal.GenerateKey(ctx, bits)
+				if err != nil {
+					return nil, err
+				}
+				traceValue(0, "p", "safe prime", key.P)
+				traceValue(0, "g", "primitive root modulo p", key.G)
+				traceValue(0, "x", "random secret exponent", key.X)
+				traceValue(0, "y", "g^x mod p", key.Y)
+				return []*big.Int{key.P, key.G, key.Y, key.X}, nil
+			})
+		},
+		Sign: func(message []byte, keyString string) (string, error) {
+			key, err := verify.ElGamalKey(keyString)
+			if err != nil {
+				return "", err
+			}
+			sig, err := elgamal.Sign(key, message)
+			if err != nil {
+				return "", err
+			}
+			traceValue(0, "h", "hash of the message", sig.H)
+			traceValue(0, "k", "random exponent coprime with p-1", sig.K)
+			traceValue(0, "k^-1", "k^-1 mod (p-1)", sig.KInverse)
+			traceValue(0, "r", "g^k mod p", sig.R)
+			traceValue(0, "s", "(h-x*r)*k^-1 mod (p-1)", sig.S)
+			return sig.R.String() + "," + sig.S.String(), nil
+		},
+		Verify: func(message []byte, keyString, signature string) error {
+			key, err := verify.ElGamalKey(keyString)
+			if err != nil {
+				return err
+			}
+			numbers, err := asymmetric.ParseNumbers(strings.ReplaceAll(signature, ",", " "))
+			if err != nil || len(numbers) != 2 {
+				return fmt.Errorf("elgamal signature must look like r,s")
+			}
+			v, err := elgamal.Verify(&key.PublicKey, message, numbers[0], numbers[1])
+			if v != nil {
+				traceValue(0, "h", "hash of the message", v.H)
+				traceValue(0, "left", "y^r*r^s mod p", v.Left)
+				traceValue(0, "right", "g^h mod p", v.Right)
+			}
+			return err
+		},
+	}
+}
+
+func shamirCipher() Cipher {
+	return Cipher{
+		ID:         "shamir",
+		Name:       "Shamir three-pass protocol (alphabet text in blocks)",
+		KeyFormat:  "p|cA|cB in decimal, or new|bits to generate a prime and the exponents",
+		KeySize:    numberBits,
+		Ciphertext: Digits,
+		Encrypt: func(input, keyString string, alphabetMap map[rune]int, power int) (string, error) {
+			pr, err := verify.ShamirKey(keyString)
+			if err != nil {
+				return "", err
+			}
+			blocks, err := asymmetric.EncodeText(input, alphabetMap, power, pr.P)
+			if err != nil {
+				return "", err
+			}
+
+			// шифртекст - это три сообщения, переданные по открытому каналу
+			var ciphertext []*big.Int
+			for i, m := range blocks {
+				pass, err := pr.Send(m)
+				if err != nil {
+					return "", err
+				}
+				traceValue(i+1, "m", "block of the text", m)
+				traceValue(i+1, "x1", "m^cA mod p", pass.X1)
+				traceValue(i+1, "x2", "x1^cB mod p", pass.X2)
+				traceValue(i+1, "x3", "x2^dA mod p", pass.X3)
+				ciphertext = append(ciphertext, pass.X1, pass.X2, pass.X3)
+			}
+			return asymmetric.FormatNumbers(ciphertext), nil
+		},
+		Decrypt: func(input, keyString string, alphabetMap map[rune]int, power int) (string, error) {
+			pr, err := verify.ShamirKey(keyString)
+			if err != nil {
+				return "", err
+			}
+			passes, err := groups(input, 3)
+			if err != nil {
+				return "", err
+			}
+
+			var blocks []*big.Int
+			for i, pass := range passes {
+				m, err := pr.Receive(pass[2])
+				if err != nil {
+					return "", err
+				}
+				traceValue(i+1, "m", "x3^dB mod p", m)
+				blocks = append(blocks, m)
+			}
+			return asymmetric.DecodeText(blocks, alphabetMap, power)
+		},
+		NewKey: func(ctx context.Context, params string, alphabetMap map[rune]int, power int) (string, bool, error) {
+			return newKeyOfSize(params, "shamir", func(bits int) ([]*big.Int, error) {
+				pr, err := shamir.Generate(ctx, bits)
+				if err != nil {
+					return nil, err
+				}
+				traceValue(0, "p", "random prime", pr.P)
+				traceValue(0, "cA", "coprime with p-1", pr.Alice.C)
+				traceValue(0, "dA", "cA^-1 mod (p-1)", pr.Alice.D)
+				traceValue(0, "cB", "coprime with p-1", pr.Bob.C)
+				traceValue(0, "dB", "cB^-1 mod (p-1)", pr.Bob.D)
+				return []*big.Int{pr.P, pr.Alice.C, pr.Bob.C}, nil
+			})
+		},
+	}
+}
+
+// gost3410Cipher registers GOST R 34.10-2012 signatures, the scheme can not encrypt
+func gost3410Cipher() Cipher {
+	return Cipher{
+		ID:        "gost3410",
+		Name:      "GOST R 34.10-2012 signature",
+		KeyFormat: "curve|d, curve|x|y to verify only, or new|curve to generate a key",
+		NewKey: func(ctx context.Context, params string, alphabetMap map[rune]int, power int) (string, bool, error) {
+			parts := strings.Split(params, keys.Delimiter)
+			if parts[0] != "new" {
+				return "", false, nil
+			}
+			if len(parts) != 2 {
+				return "", false, fmt.Errorf("gost 34.10 generation key must look like new%scurve", keys.Delimiter)
+			}
+
+			c, err := ecc.Lookup(parts[1])
+			if err != nil {
+				return "", false, err
+			}
+			key, err := ecc.GenerateKey(c)
+			if err != nil {
+				return "", false, err
+			}
+			traceValue(0, "d", "random secret key in [1, q-1]", key.D)
+			traceValue(0, "Q", "d*G", key.Public)
+			return c.Name + keys.Delimiter + key.D.String(), true, nil
+		},
+		Sign: func(message []byte, keyString string) (string, error) {
+			key, err := verify.ECCKey(keyString)
+			if err != nil {
+				return "", err
+			}
+			if key.D == nil {
+				return "", fmt.Errorf("signing needs the secret key")
+			}
+
+			sig, err := ecc.Sign(key, message)
+			if err != nil {
+				return "", err
+			}
+			traceValue(0, "e", "hash of the message mod q", sig.E)
+			traceValue(0, "k", "random nonce in [1, q-1]", sig.K)
+			traceValue(0, "C", "k*G", sig.C)
+			traceValue(0, "r", "x of C mod q", sig.R)
+			traceValue(0, "s", "(r*d + k*e) mod q", sig.S)
+
+			// подпись - это вектор r||s, каждая половина длиной в q
+			size := (key.Curve.Q.BitLen() + 7) / 8
+			signature := append(sig.R.FillBytes(make([]byte, size)), sig.S.FillBytes(make([]byte, size))...)
+			return hex.EncodeToString(signature), nil
+		},
+		Verify: func(message []byte, keyString, signature string) error {
+			key, err := verify.ECCKey(keyString)
+			if err != nil {
+				return err
+			}
+
+			size := (key.Curve.Q.BitLen() + 7) / 8
+			raw, err := hex.DecodeString(signature)
+			if err != nil || len(raw) != 2*size {
+				return fmt.Errorf("gost 34.10 signature must be %d hex digits", 4*size)
+			}
+			r, s := new(big.Int).SetBytes(raw[:size]), new(big.Int).SetBytes(raw[size:])
+
+			v, err := ecc.Verify(key.Curve, key.Public, message, r, s)
+			if v != nil {
+				traceValue(0, "e", "hash of the message mod q", v.E)
+				traceValue(0, "v", "e^-1 mod q", v.V)
+				traceValue(0, "z1", "s*v mod q", v.Z1)
+				traceValue(0, "z2", "-r*v mod q", v.Z2)
+				traceValue(0, "C", "z1*G + z2*Q", v.C)
+			}
+			return err
+		},
+	}
+}
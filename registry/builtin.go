@@ -0,0 +1,741 @@
+package registry
+
+import (
+	"context"
+	"crypto/cipher"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/marelinaa/cipher-algorithms/asymmetric"
+	"github.com/marelinaa/cipher-algorithms/asymmetric/rsa"
+	"github.com/marelinaa/cipher-algorithms/decrypt"
+	"github.com/marelinaa/cipher-algorithms/encrypt"
+	"github.com/marelinaa/cipher-algorithms/gost"
+	"github.com/marelinaa/cipher-algorithms/keygen"
+	"github.com/marelinaa/cipher-algorithms/keys"
+	"github.com/marelinaa/cipher-algorithms/pad"
+	"github.com/marelinaa/cipher-algorithms/stream"
+	"github.com/marelinaa/cipher-algorithms/verify"
+)
+
+func init() {
+	Register(Cipher{
+		ID:        "caesar",
+		Name:      "Caesar cipher",
+		KeyFormat: "one symbol from the alphabet",
+		Encrypt: func(input, keyString string, alphabetMap map[rune]int, power int) (string, error) {
+			key, err := verify.CaesarKey(keyString, alphabetMap)
+			if err != nil {
+				return "", err
+			}
+			return encrypt.Caesar(input, key, alphabetMap, power)
+		},
+		Decrypt: func(input, keyString string, alphabetMap map[rune]int, power int) (string, error) {
+			key, err := verify.CaesarKey(keyString, alphabetMap)
+			if err != nil {
+				return "", err
+			}
+			return decrypt.Caesar(input, key, alphabetMap, power)
+		},
+	})
+
+	Register(Cipher{
+		ID:        "affine",
+		Name:      "Affine cipher",
+		KeyFormat: "two symbols from the alphabet, the first coprime with the power",
+		Encrypt: func(input, keyString string, alphabetMap map[rune]int, power int) (string, error) {
+			key, err := verify.AffineKey(keyString, alphabetMap, power)
+			if err != nil {
+				return "", err
+			}
+			return encrypt.Affine(input, key, alphabetMap, power)
+		},
+		Decrypt: func(input, keyString string, alphabetMap map[rune]int, power int) (string, error) {
+			key, err := verify.AffineKey(keyString, alphabetMap, power)
+			if err != nil {
+				return "", err
+			}
+			return decrypt.Affine(input, key, alphabetMap, power)
+		},
+	})
+
+	Register(Cipher{
+		ID:        "substitution",
+		Name:      "Simple substitution cipher",
+		KeyFormat: "permutation of the alphabet, atbash, rot|n or keyword|WORD|shift",
+		Encrypt: func(input, keyString string, alphabetMap map[rune]int, power int) (string, error) {
+			// the key may be a preset (atbash, rot, keyword) instead of the full permuted alphabet
+			substitution, err := verify.ExpandSubstitutionKey(keyString, alphabetMap, power)
+			if err != nil {
+				return "", err
+			}
+			key, err := verify.SubstitutionKey(substitution, alphabetMap, power)
+			if err != nil {
+				return "", err
+			}
+			return encrypt.Substitution(input, key, alphabetMap, power)
+		},
+		Decrypt: func(input, keyString string, alphabetMap map[rune]int, power int) (string, error) {
+			substitution, err := verify.ExpandSubstitutionKey(keyString, alphabetMap, power)
+			if err != nil {
+				return "", err
+			}
+			_, err = verify.SubstitutionKey(substitution, alphabetMap, power)
+			if err != nil {
+				return "", err
+			}
+			return decrypt.Substitution(input, []rune(substitution), alphabetMap, power)
+		},
+	})
+
+	Register(Cipher{
+		ID:        "hill",
+		Name:      "Hill cipher",
+		KeyFormat: "4 symbols from the alphabet forming an invertible 2x2 matrix, optionally followed by |ecb, |cbc, |cfb, |ofb or |ctr",
+		KeyPadding: func(keyString string) Padding {
+			_, mode, _ := strings.Cut(keyString, keys.Delimiter)
+			return modePadding(mode, RandomPadding)
+		},
+		Encrypt: func(input, keyString string, alphabetMap map[rune]int, power int) (string, error) {
+			key, mode, err := verify.HillModeKey(keyString, alphabetMap, power)
+			if err != nil {
+				return "", err
+			}
+			if mode != "" {
+				return encrypt.HillMode(input, key, mode, alphabetMap, power)
+			}
+			return encrypt.Hill(input, key, alphabetMap, power)
+		},
+		Decrypt: func(input, keyString string, alphabetMap map[rune]int, power int) (string, error) {
+			key, mode, err := verify.HillModeKey(keyString, alphabetMap, power)
+			if err != nil {
+				return "", err
+			}
+			if mode != "" {
+				return decrypt.HillMode(input, key, mode, alphabetMap, power)
+			}
+			return decrypt.Hill(input, key, alphabetMap, power)
+		},
+	})
+
+	Register(Cipher{
+		ID:        "permutation",
+		Name:      "Permutation cipher",
+		KeyFormat: "keyword without repeated symbols",
+		Padding:   RandomPadding,
+		Encrypt: func(input, keyString string, alphabetMap map[rune]int, power int) (string, error) {
+			err := verify.PermutationKey(keyString, alphabetMap, power)
+			if err != nil {
+				return "", err
+			}
+			return encrypt.Permutation(input, keyString, alphabetMap, power)
+		},
+		Decrypt: func(input, keyString string, alphabetMap map[rune]int, power int) (string, error) {
+			err := verify.PermutationKey(keyString, alphabetMap, power)
+			if err != nil {
+				return "", err
+			}
+			return decrypt.Permutation(input, keyString, alphabetMap)
+		},
+	})
+
+	Register(Cipher{
+		ID:        "vigenere",
+		Name:      "Vigenere cipher",
+		KeyFormat: "keyword from the alphabet",
+		Encrypt: func(input, keyString string, alphabetMap map[rune]int, power int) (string, error) {
+			err := verify.VigenereKey(keyString, alphabetMap, power)
+			if err != nil {
+				return "", err
+			}
+			return encrypt.Vigenere(input, keyString, alphabetMap)
+		},
+		Decrypt: func(input, keyString string, alphabetMap map[rune]int, power int) (string, error) {
+			err := verify.VigenereKey(keyString, alphabetMap, power)
+			if err != nil {
+				return "", err
+			}
+			return decrypt.Vigenere(input, keyString, alphabetMap)
+		},
+	})
+
+	Register(Cipher{
+		ID:        "columnar",
+		Name:      "Columnar transposition",
+		KeyFormat: "keyword without repeated symbols",
+		Encrypt: func(input, keyString string, alphabetMap map[rune]int, power int) (string, error) {
+			err := verify.PermutationKey(keyString, alphabetMap, power)
+			if err != nil {
+				return "", err
+			}
+			return encrypt.Columnar(input, keyString, alphabetMap)
+		},
+		Decrypt: func(input, keyString string, alphabetMap map[rune]int, power int) (string, error) {
+			err := verify.PermutationKey(keyString, alphabetMap, power)
+			if err != nil {
+				return "", err
+			}
+			return decrypt.Columnar(input, keyString, alphabetMap)
+		},
+	})
+
+	Register(Cipher{
+		ID:        "myszkowski",
+		Name:      "Myszkowski transposition",
+		KeyFormat: "keyword, repeated symbols allowed",
+		Encrypt: func(input, keyString string, alphabetMap map[rune]int, power int) (string, error) {
+			err := verify.MyszkowskiKey(keyString, alphabetMap)
+			if err != nil {
+				return "", err
+			}
+			return encrypt.Myszkowski(input, keyString, alphabetMap)
+		},
+		Decrypt: func(input, keyString string, alphabetMap map[rune]int, power int) (string, error) {
+			err := verify.MyszkowskiKey(keyString, alphabetMap)
+			if err != nil {
+				return "", err
+			}
+			return decrypt.Myszkowski(input, keyString, alphabetMap)
+		},
+	})
+
+	Register(Cipher{
+		ID:        "double",
+		Name:      "Double transposition",
+		KeyFormat: "two keywords without repeated symbols: first|second",
+		Encrypt: func(input, keyString string, alphabetMap map[rune]int, power int) (string, error) {
+			key, err := verify.DoubleTranspositionKey(keyString, alphabetMap, power)
+			if err != nil {
+				return "", err
+			}
+			return encrypt.DoubleTransposition(input, key, alphabetMap)
+		},
+		Decrypt: func(input, keyString string, alphabetMap map[rune]int, power int) (string, error) {
+			key, err := verify.DoubleTranspositionKey(keyString, alphabetMap, power)
+			if err != nil {
+				return "", err
+			}
+			return decrypt.DoubleTransposition(input, key, alphabetMap)
+		},
+	})
+
+	Register(Cipher{
+		ID:        "enigma",
+		Name:      "Enigma",
+		KeyFormat: "model|reflector|rotors|rings|positions|plugboard, e.g. M3|B|I,II,III|AAA|AAA|ABCD",
+		Encrypt: func(input, keyString string, alphabetMap map[rune]int, power int) (string, error) {
+			key, err := verify.EnigmaKey(keyString, alphabetMap, power)
+			if err != nil {
+				return "", err
+			}
+			return encrypt.Enigma(input, key, alphabetMap, power)
+		},
+		Decrypt: func(input, keyString string, alphabetMap map[rune]int, power int) (string, error) {
+			key, err := verify.EnigmaKey(keyString, alphabetMap, power)
+			if err != nil {
+				return "", err
+			}
+			return decrypt.Enigma(input, key, alphabetMap, power)
+		},
+	})
+
+	Register(Cipher{
+		ID:        "homophonic",
+		Name:      "Homophonic substitution",
+		KeyFormat: "char:code,code|char:code or the number of codes to generate a table",
+		Encrypt: func(input, keyString string, alphabetMap map[rune]int, power int) (string, error) {
+			key, err := verify.HomophonicKey(keyString, alphabetMap)
+			if err != nil {
+				return "", err
+			}
+			return encrypt.Homophonic(input, key)
+		},
+		Decrypt: func(input, keyString string, alphabetMap map[rune]int, power int) (string, error) {
+			key, err := verify.HomophonicKey(keyString, alphabetMap)
+			if err != nil {
+				return "", err
+			}
+			return decrypt.Homophonic(input, key)
+		},
+		Ciphertext: Digits,
+		NewKey: func(ctx context.Context, params string, alphabetMap map[rune]int, power int) (string, bool, error) {
+			// a number in the key file asks to generate a table with that many codes
+			total, err := strconv.Atoi(params)
+			if err != nil {
+				return "", false, nil
+			}
+			key, err := keygen.Homophonic(alphabetMap, power, total)
+			if err != nil {
+				return "", false, err
+			}
+			return key.String(), true, nil
+		},
+	})
+
+	Register(Cipher{
+		ID:        "trithemius",
+		Name:      "Trithemius cipher",
+		KeyFormat: "initial shift symbol with optional step: symbol or symbol|step",
+		Encrypt: func(input, keyString string, alphabetMap map[rune]int, power int) (string, error) {
+			key, err := verify.TrithemiusKey(keyString, alphabetMap)
+			if err != nil {
+				return "", err
+			}
+			return encrypt.Trithemius(input, key, alphabetMap, power)
+		},
+		Decrypt: func(input, keyString string, alphabetMap map[rune]int, power int) (string, error) {
+			key, err := verify.TrithemiusKey(keyString, alphabetMap)
+			if err != nil {
+				return "", err
+			}
+			return decrypt.Trithemius(input, key, alphabetMap, power)
+		},
+	})
+
+	Register(Cipher{
+		ID:        "alberti",
+		Name:      "Alberti cipher disk",
+		KeyFormat: "inner ring keyword, index symbol and period: keyword|index|period",
+		Encrypt: func(input, keyString string, alphabetMap map[rune]int, power int) (string, error) {
+			key, err := verify.AlbertiKey(keyString, alphabetMap, power)
+			if err != nil {
+				return "", err
+			}
+			return encrypt.Alberti(input, key, alphabetMap, power)
+		},
+		Decrypt: func(input, keyString string, alphabetMap map[rune]int, power int) (string, error) {
+			key, err := verify.AlbertiKey(keyString, alphabetMap, power)
+			if err != nil {
+				return "", err
+			}
+			return decrypt.Alberti(input, key, alphabetMap, power)
+		},
+	})
+
+	Register(Cipher{
+		ID:        "porta",
+		Name:      "Porta cipher",
+		KeyFormat: "keyword from the alphabet, the power of the alphabet must be even",
+		Encrypt: func(input, keyString string, alphabetMap map[rune]int, power int) (string, error) {
+			err := verify.PortaKey(keyString, alphabetMap, power)
+			if err != nil {
+				return "", err
+			}
+			return encrypt.Porta(input, keyString, alphabetMap, power)
+		},
+		Decrypt: func(input, keyString string, alphabetMap map[rune]int, power int) (string, error) {
+			err := verify.PortaKey(keyString, alphabetMap, power)
+			if err != nil {
+				return "", err
+			}
+			return decrypt.Porta(input, keyString, alphabetMap, power)
+		},
+	})
+
+	Register(Cipher{
+		ID:        "vic",
+		Name:      "Straddling checkerboard (VIC)",
+		KeyFormat: "keyword|blank digits|chain addition seed|transposition digits, the last two are optional and the seed may be empty",
+		Encrypt: func(input, keyString string, alphabetMap map[rune]int, power int) (string, error) {
+			key, err := verify.VICKey(keyString, alphabetMap, power)
+			if err != nil {
+				return "", err
+			}
+			return encrypt.VIC(input, key)
+		},
+		Decrypt: func(input, keyString string, alphabetMap map[rune]int, power int) (string, error) {
+			key, err := verify.VICKey(keyString, alphabetMap, power)
+			if err != nil {
+				return "", err
+			}
+			return decrypt.VIC(input, key)
+		},
+		Ciphertext: Digits,
+	})
+
+	Register(Cipher{
+		ID:        "otp",
+		Name:      "One-time pad (Vernam over the alphabet)",
+		KeyFormat: "path|offset of the pad segment, pad file path to use its next unused segment, or new|path|length to generate a pad",
+		Files:     true,
+		Encrypt: func(input, keyString string, alphabetMap map[rune]int, power int) (string, error) {
+			key, err := verify.PadKey(keyString, true)
+			if err != nil {
+				return "", err
+			}
+			_, segment, err := pad.Take(key.Path, pad.Alphabet, key.Offset, utf8.RuneCountInString(input))
+			if err != nil {
+				return "", err
+			}
+			return encrypt.Vernam(input, segment, alphabetMap, power)
+		},
+		Decrypt: func(input, keyString string, alphabetMap map[rune]int, power int) (string, error) {
+			key, err := verify.PadKey(keyString, true)
+			if err != nil {
+				return "", err
+			}
+			segment, err := pad.Segment(key.Path, pad.Alphabet, key.Offset, utf8.RuneCountInString(input))
+			if err != nil {
+				return "", err
+			}
+			return decrypt.Vernam(input, segment, alphabetMap, power)
+		},
+		NewKey: func(ctx context.Context, params string, alphabetMap map[rune]int, power int) (string, bool, error) {
+			return newPad(params, pad.Alphabet, func(path string, n int) error {
+				return pad.GenerateAlphabet(path, n, alphabetMap, power)
+			})
+		},
+	})
+
+	Register(Cipher{
+		ID:         "xor",
+		Name:       "One-time pad (XOR over bytes)",
+		KeyFormat:  "path|offset of the pad segment, pad file path to use its next unused segment, or new|path|length to generate a pad",
+		Plaintext:  Bytes,
+		Ciphertext: Hex,
+		Files:      true,
+		Encrypt: func(input, keyString string, alphabetMap map[rune]int, power int) (string, error) {
+			key, err := verify.PadKey(keyString, true)
+			if err != nil {
+				return "", err
+			}
+			_, segment, err := pad.Take(key.Path, pad.Bytes, key.Offset, len(input))
+			if err != nil {
+				return "", err
+			}
+			padBytes, err := hex.DecodeString(segment)
+			if err != nil {
+				return "", err
+			}
+			return hex.EncodeToString(encrypt.XOR([]byte(input), padBytes)), nil
+		},
+		Decrypt: func(input, keyString string, alphabetMap map[rune]int, power int) (string, error) {
+			key, err := verify.PadKey(keyString, true)
+			if err != nil {
+				return "", err
+			}
+			cipherBytes, err := hex.DecodeString(input)
+			if err != nil {
+				return "", fmt.Errorf("ciphertext must be written in hex: %v", err)
+			}
+			segment, err := pad.Segment(key.Path, pad.Bytes, key.Offset, len(cipherBytes))
+			if err != nil {
+				return "", err
+			}
+			padBytes, err := hex.DecodeString(segment)
+			if err != nil {
+				return "", err
+			}
+			return string(encrypt.XOR(cipherBytes, padBytes)), nil
+		},
+		NewKey: func(ctx context.Context, params string, alphabetMap map[rune]int, power int) (string, bool, error) {
+			return newPad(params, pad.Bytes, pad.GenerateBytes)
+		},
+	})
+
+	Register(blockCipher("magma", "Magma (GOST R 34.12-2015, 64-bit block)", gost.NewMagma))
+	Register(blockCipher("kuznyechik", "Kuznyechik (GOST R 34.12-2015, 128-bit block)", gost.NewKuznyechik))
+
+	Register(quagmire(1, "I", "keyed plaintext alphabet and indicator: keyword|indicator"))
+	Register(quagmire(2, "II", "keyed ciphertext alphabet and indicator: keyword|indicator"))
+	Register(quagmire(3, "III", "keyword for both alphabets and indicator: keyword|indicator"))
+	Register(quagmire(4, "IV", "plaintext and ciphertext keywords and indicator: plainKeyword|cipherKeyword|indicator"))
+
+	Register(streamCipher("lfsr", "LFSR stream cipher", "kind (fibonacci, galois)|taps|seed in binary, e.g. galois|16,14,13,11|1010110011100001",
+		func(keyString string) (stream.Generator, error) {
+			key, err := verify.LFSRKey(keyString)
+			if err != nil {
+				return nil, err
+			}
+			return stream.NewLFSR(key.Kind, key.Taps, key.Seed)
+		}))
+	Register(streamCipher("a51", "A5/1 stream cipher", "64-bit key in hex|frame number, e.g. 1223456789abcdef|0x134",
+		func(keyString string) (stream.Generator, error) {
+			key, err := verify.A51Key(keyString)
+			if err != nil {
+				return nil, err
+			}
+			return stream.NewA51(key.Key, key.Frame), nil
+		}))
+	Register(streamCipher("rc4", "RC4 stream cipher", "any text of 1 to 256 bytes",
+		func(keyString string) (stream.Generator, error) {
+			return stream.NewRC4([]byte(keyString))
+		}))
+
+	Register(Cipher{
+		ID:         "rsa",
+		Name:       "RSA (textbook, alphabet text in blocks)",
+		KeyFormat:  "n|e|d in decimal, n|e to encrypt only, or new|bits to generate a key",
+		KeySize:    numberBits,
+		Ciphertext: Digits,
+		Encrypt: func(input, keyString string, alphabetMap map[rune]int, power int) (string, error) {
+			key, err := verify.RSAKey(keyString)
+			if err != nil {
+				return "", err
+			}
+			blocks, err := rsa.EncryptText(&key.PublicKey, input, alphabetMap, power)
+			if err != nil {
+				return "", err
+			}
+			return asymmetric.FormatNumbers(blocks), nil
+		},
+		Decrypt: func(input, keyString string, alphabetMap map[rune]int, power int) (string, error) {
+			key, err := verify.RSAKey(keyString)
+			if err != nil {
+				return "", err
+			}
+			blocks, err := asymmetric.ParseNumbers(input)
+			if err != nil {
+				return "", err
+			}
+			return rsa.DecryptText(key, blocks, alphabetMap, power)
+		},
+		NewKey: newRSA,
+		Sign: func(message []byte, keyString string) (string, error) {
+			key, err := verify.RSAKey(keyString)
+			if err != nil {
+				return "", err
+			}
+			s, err := rsa.Sign(key, message)
+			if err != nil {
+				return "", err
+			}
+			return s.String(), nil
+		},
+		Verify: func(message []byte, keyString, signature string) error {
+			key, err := verify.RSAKey(keyString)
+			if err != nil {
+				return err
+			}
+			s, ok := new(big.Int).SetString(signature, 10)
+			if !ok {
+				return fmt.Errorf("rsa signature must be a decimal number")
+			}
+			return rsa.Verify(&key.PublicKey, message, s)
+		},
+	})
+
+	Register(Cipher{
+		ID:         "rsa-oaep",
+		Name:       "RSA-OAEP (SHA-256)",
+		KeyFormat:  "n|e|d in decimal, n|e to encrypt only, or new|bits to generate a key of at least 528 bits",
+		KeySize:    numberBits,
+		Plaintext:  Bytes,
+		Ciphertext: Hex,
+		Encrypt: func(input, keyString string, alphabetMap map[rune]int, power int) (string, error) {
+			key, err := verify.RSAKey(keyString)
+			if err != nil {
+				return "", err
+			}
+			chunk := rsa.MaxOAEPMessage(&key.PublicKey)
+			if chunk < 1 {
+				return "", fmt.Errorf("%d-bit key is too small for oaep", key.N.BitLen())
+			}
+
+			// длинный текст шифруется несколькими блоками OAEP
+			var ciphertext []byte
+			message := []byte(input)
+			for i := 0; i == 0 || i < len(message); i += chunk {
+				block, err := rsa.EncryptOAEP(&key.PublicKey, message[i:min(i+chunk, len(message))], nil)
+				if err != nil {
+					return "", err
+				}
+				ciphertext = append(ciphertext, block...)
+			}
+			return hex.EncodeToString(ciphertext), nil
+		},
+		Decrypt: func(input, keyString string, alphabetMap map[rune]int, power int) (string, error) {
+			key, err := verify.RSAKey(keyString)
+			if err != nil {
+				return "", err
+			}
+			ciphertext, err := hex.DecodeString(input)
+			if err != nil {
+				return "", fmt.Errorf("ciphertext must be written in hex: %v", err)
+			}
+
+			size := (key.N.BitLen() + 7) / 8
+			if len(ciphertext)%size != 0 {
+				return "", fmt.Errorf("ciphertext length must be a multiple of the key size %d", size)
+			}
+			var plaintext []byte
+			for i := 0; i < len(ciphertext); i += size {
+				block, err := rsa.DecryptOAEP(key, ciphertext[i:i+size], nil)
+				if err != nil {
+					return "", err
+				}
+				plaintext = append(plaintext, block...)
+			}
+			return string(plaintext), nil
+		},
+		NewKey: newRSA,
+	})
+
+	Register(elgamalCipher())
+	Register(shamirCipher())
+	Register(gost3410Cipher())
+}
+
+// newPad completes the pad key before the operation, so the offset is saved with the key and the message
+// can be decrypted. A bare path gets the next unused offset of the pad, new|path|length generates a pad
+// and gets offset 0. A key with an offset is returned as not generated
+func newPad(params string, kind pad.Kind, generate func(path string, n int) error) (string, bool, error) {
+	parts := strings.Split(params, keys.Delimiter)
+	if parts[0] != "new" {
+		key, err := verify.PadKey(params, false)
+		if err != nil || key.Offset >= 0 {
+			return "", false, err
+		}
+		p, err := pad.Read(key.Path)
+		if err != nil {
+			return "", false, err
+		}
+		if p.Kind != kind {
+			return "", false, fmt.Errorf("pad %s holds %s, not %s", key.Path, p.Kind, kind)
+		}
+		return fmt.Sprintf("%s%s%d", key.Path, keys.Delimiter, p.Offset), true, nil
+	}
+	if len(parts) != 3 {
+		return "", false, fmt.Errorf("pad generation key must look like new%[1]spath%[1]slength", keys.Delimiter)
+	}
+
+	n, err := strconv.Atoi(parts[2])
+	if err != nil || n < 1 {
+		return "", false, fmt.Errorf("pad length must be a positive number: %q", parts[2])
+	}
+
+	err = generate(parts[1], n)
+	if err != nil {
+		return "", false, err
+	}
+
+	return parts[1] + keys.Delimiter + "0", true, nil
+}
+
+func encode(data []byte, encoding string) string {
+	if encoding == verify.EncodingBase64 {
+		return base64.StdEncoding.EncodeToString(data)
+	}
+	return hex.EncodeToString(data)
+}
+
+func decode(input, encoding string) ([]byte, error) {
+	if encoding == verify.EncodingBase64 {
+		return base64.StdEncoding.DecodeString(input)
+	}
+	return hex.DecodeString(input)
+}
+
+// modePadding returns the padding of a mode of operation: only ECB and CBC (and no mode at all)
+// extend the text to whole blocks, the other modes xor the text with the gamma
+func modePadding(mode string, padding Padding) Padding {
+	switch mode {
+	case "", encrypt.ModeECB, encrypt.ModeCBC:
+		return padding
+	}
+	return NoPadding
+}
+
+// blockCipher registers a byte-oriented block cipher working in the mode given in the key
+func blockCipher(id, name string, newBlock func(key []byte) (cipher.Block, error)) Cipher {
+	return Cipher{
+		ID:            id,
+		Name:          name,
+		KeyFormat:     "256-bit key in hex with optional mode (ecb, cbc, cfb, ofb, ctr) and encoding (hex, base64): hexkey|cbc|base64",
+		Plaintext:     Bytes,
+		Ciphertext:    Hex,
+		Fingerprinted: true,
+		KeyPadding: func(keyString string) Padding {
+			key, err := verify.BlockKey(keyString, gost.KeySize)
+			if err != nil {
+				return GOSTPadding
+			}
+			return modePadding(key.Mode, GOSTPadding)
+		},
+		Encrypt: func(input, keyString string, alphabetMap map[rune]int, power int) (string, error) {
+			key, err := verify.BlockKey(keyString, gost.KeySize)
+			if err != nil {
+				return "", err
+			}
+			b, err := newBlock(key.Key)
+			if err != nil {
+				return "", err
+			}
+			ciphertext, err := encrypt.BlockMode(b, key.Mode, []byte(input))
+			if err != nil {
+				return "", err
+			}
+			return encode(ciphertext, key.Encoding), nil
+		},
+		Decrypt: func(input, keyString string, alphabetMap map[rune]int, power int) (string, error) {
+			key, err := verify.BlockKey(keyString, gost.KeySize)
+			if err != nil {
+				return "", err
+			}
+			b, err := newBlock(key.Key)
+			if err != nil {
+				return "", err
+			}
+			ciphertext, err := decode(input, key.Encoding)
+			if err != nil {
+				return "", fmt.Errorf("ciphertext must be written in %s: %v", key.Encoding, err)
+			}
+			plaintext, err := decrypt.BlockMode(b, key.Mode, ciphertext)
+			if err != nil {
+				return "", err
+			}
+			return string(plaintext), nil
+		},
+	}
+}
+
+// streamCipher registers a keystream generator used for Vernam over the alphabet
+func streamCipher(id, name, keyFormat string, keystream func(key string) (stream.Generator, error)) Cipher {
+	return Cipher{
+		ID:        id,
+		Name:      name,
+		KeyFormat: keyFormat,
+		Keystream: keystream,
+		Encrypt: func(input, keyString string, alphabetMap map[rune]int, power int) (string, error) {
+			g, err := keystream(keyString)
+			if err != nil {
+				return "", err
+			}
+			return encrypt.Stream(input, g, alphabetMap, power)
+		},
+		Decrypt: func(input, keyString string, alphabetMap map[rune]int, power int) (string, error) {
+			g, err := keystream(keyString)
+			if err != nil {
+				return "", err
+			}
+			return decrypt.Stream(input, g, alphabetMap, power)
+		},
+	}
+}
+
+func quagmire(variant int, numeral, keyFormat string) Cipher {
+	return Cipher{
+		ID:        "quagmire" + strconv.Itoa(variant),
+		Name:      "Quagmire " + numeral,
+		KeyFormat: keyFormat,
+		Encrypt: func(input, keyString string, alphabetMap map[rune]int, power int) (string, error) {
+			key, err := verify.QuagmireKey(keyString, variant, alphabetMap, power)
+			if err != nil {
+				return "", err
+			}
+			return encrypt.Quagmire(input, key)
+		},
+		Decrypt: func(input, keyString string, alphabetMap map[rune]int, power int) (string, error) {
+			key, err := verify.QuagmireKey(keyString, variant, alphabetMap, power)
+			if err != nil {
+				return "", err
+			}
+			return decrypt.Quagmire(input, key)
+		},
+	}
+}
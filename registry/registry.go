@@ -0,0 +1,124 @@
+package registry
+
+import (
+	"context"
+
+	"github.com/marelinaa/cipher-algorithms/stream"
+)
+
+// Format describes what a cipher reads or writes
+type Format int
+
+const (
+	Alphabet Format = iota // symbols of the alphabet
+	Digits                 // decimal digits
+	Bytes                  // any text, processed as bytes
+	Hex                    // bytes written in hexadecimal (or base64 when the key asks for it)
+)
+
+func (f Format) String() string {
+	switch f {
+	case Alphabet:
+		return "alphabet"
+	case Digits:
+		return "digits"
+	case Bytes:
+		return "bytes"
+	case Hex:
+		return "hex"
+	}
+	return "unknown"
+}
+
+// Padding describes how the encryption extends the text to whole blocks
+type Padding string
+
+const (
+	NoPadding     Padding = "none"
+	RandomPadding Padding = "random"       // random symbols of the alphabet, they stay in the decrypted text
+	GOSTPadding   Padding = "gost-34.13-2" // 0x80 and zero bytes, removed by the decryption
+)
+
+// Func encrypts or decrypts the input with a key written the way it is stored in key.txt
+type Func func(input, key string, alphabetMap map[rune]int, power int) (string, error)
+
+// Cipher describes an algorithm available in the menu
+type Cipher struct {
+	ID        string // short name used to look the cipher up
+	Name      string // name shown in the menu
+	KeyFormat string // how the key is written in key.txt
+	Encrypt   Func   // nil for schemes that only sign
+	Decrypt   Func
+
+	// Plaintext and Ciphertext describe the text before and after encryption, Alphabet by default
+	Plaintext  Format
+	Ciphertext Format
+
+	// Padding is how the cipher pads the plaintext, NoPadding when it is empty
+	Padding Padding
+	// KeyPadding is set when the padding depends on the key (e.g. on its mode of operation), it replaces Padding
+	KeyPadding func(key string) Padding
+
+	// Fingerprinted is set when the key space is too large to search, so the fingerprint of a key
+	// can be written next to the ciphertext without revealing the key
+	Fingerprinted bool
+
+	// Files is set when the key names files on disk (e.g. a pad), such ciphers are not served over the network
+	Files bool
+
+	// KeySize returns the size in bits of the largest number of the key, it is nil for ciphers without
+	// numeric keys. The key is not validated, so the size can be limited before the checks of primality
+	KeySize func(key string) int
+
+	// NewKey generates a key from the parameters written in key.txt (e.g. the number of homophonic codes).
+	// It returns false when key.txt already holds a key. A canceled context stops the search for primes.
+	NewKey func(ctx context.Context, params string, alphabetMap map[rune]int, power int) (string, bool, error)
+
+	// Keystream creates the generator of a stream cipher, it is nil for other ciphers
+	Keystream func(key string) (stream.Generator, error)
+
+	// Sign and Verify make and check signatures of files, they are nil for ciphers that can not sign
+	Sign   func(message []byte, key string) (string, error)
+	Verify func(message []byte, key, signature string) error
+}
+
+// PaddingOf returns how the cipher pads the plaintext when encrypting with the key
+func (c Cipher) PaddingOf(key string) Padding {
+	padding := c.Padding
+	if c.KeyPadding != nil {
+		padding = c.KeyPadding(key)
+	}
+	if padding == "" {
+		return NoPadding
+	}
+	return padding
+}
+
+var ciphers []Cipher
+
+// Register adds the cipher to the registry, the ciphers are listed in the order of registration
+func Register(c Cipher) {
+	for _, registered := range ciphers {
+		if registered.ID == c.ID {
+			panic("registry: cipher " + c.ID + " is registered twice")
+		}
+	}
+	ciphers = append(ciphers, c)
+}
+
+// All returns the registered ciphers
+func All() []Cipher {
+	list := make([]Cipher, len(ciphers))
+	copy(list, ciphers)
+	return list
+}
+
+// Lookup finds the cipher by its ID
+func Lookup(id string) (Cipher, bool) {
+	for _, c := range ciphers {
+		if c.ID == id {
+			return c, true
+		}
+	}
+	return Cipher{}, false
+}
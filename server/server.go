@@ -0,0 +1,312 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/marelinaa/cipher-algorithms/encrypt"
+	"github.com/marelinaa/cipher-algorithms/frequency"
+	"github.com/marelinaa/cipher-algorithms/registry"
+	"github.com/marelinaa/cipher-algorithms/verify"
+)
+
+// DefaultMaxBody is the default limit of the request body in bytes
+const DefaultMaxBody = 1 << 20
+
+const (
+	// maxKeyBits limits the keys generated for a request and the keys sent with it:
+	// larger primes take too long to find and larger moduli too long to exponentiate
+	maxKeyBits = 2048
+	// maxKeygens is the number of key generations running at once, more requests are refused
+	maxKeygens = 2
+)
+
+// cipherRequest is the body of the encrypt and decrypt requests
+type cipherRequest struct {
+	Alphabet string `json:"alphabet"`
+	Key      string `json:"key"`
+	Text     string `json:"text"`
+}
+
+// keygenRequest is the body of the keygen request, params are written as in key.txt (e.g. new|1024)
+type keygenRequest struct {
+	Cipher   string `json:"cipher"`
+	Alphabet string `json:"alphabet"`
+	Params   string `json:"params"`
+}
+
+// analyzeRequest is the body of the analyze request
+type analyzeRequest struct {
+	Alphabet string `json:"alphabet"`
+	Text     string `json:"text"`
+}
+
+type cipherInfo struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	KeyFormat  string `json:"key_format"`
+	Plaintext  string `json:"plaintext"`
+	Ciphertext string `json:"ciphertext"`
+	Encrypt    bool   `json:"encrypt"`
+	Keygen     bool   `json:"keygen"`
+}
+
+type resultResponse struct {
+	Result string `json:"result"`
+}
+
+type keygenResponse struct {
+	Key string `json:"key"`
+}
+
+type analyzeResponse struct {
+	Length      int                `json:"length"`
+	Counts      map[string]int     `json:"counts"`
+	Frequencies map[string]float64 `json:"frequencies"`
+	Coincidence float64            `json:"index_of_coincidence"`
+	ChiSquared  float64            `json:"chi_squared"`
+	CaesarShift int                `json:"caesar_shift"`
+}
+
+// errorResponse is returned with every failed request, Position is set when the text has an invalid symbol
+type errorResponse struct {
+	Error    string `json:"error"`
+	Position *int   `json:"position,omitempty"`
+}
+
+// New returns the handler of the JSON API. Request bodies larger than maxBody bytes are rejected.
+// Ciphers whose keys name files on disk are not served
+func New(maxBody int64) http.Handler {
+	s := &server{maxBody: maxBody, keygens: make(chan struct{}, maxKeygens)}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/ciphers", s.ciphers)
+	mux.HandleFunc("POST /v1/{cipher}/encrypt", s.crypt(true))
+	mux.HandleFunc("POST /v1/{cipher}/decrypt", s.crypt(false))
+	mux.HandleFunc("POST /v1/keygen", s.keygen)
+	mux.HandleFunc("POST /v1/analyze", s.analyze)
+	return mux
+}
+
+type server struct {
+	maxBody int64
+	keygens chan struct{} // slots of the key generations in progress
+}
+
+func (s *server) ciphers(w http.ResponseWriter, r *http.Request) {
+	list := []cipherInfo{}
+	for _, c := range registry.All() {
+		if c.Files {
+			continue
+		}
+		list = append(list, cipherInfo{
+			ID:         c.ID,
+			Name:       c.Name,
+			KeyFormat:  c.KeyFormat,
+			Plaintext:  c.Plaintext.String(),
+			Ciphertext: c.Ciphertext.String(),
+			Encrypt:    c.Encrypt != nil,
+			Keygen:     c.NewKey != nil,
+		})
+	}
+	writeJSON(w, http.StatusOK, list)
+}
+
+func (s *server) crypt(encryption bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		c, ok := lookup(w, r.PathValue("cipher"))
+		if !ok {
+			return
+		}
+
+		var req cipherRequest
+		if !s.decode(w, r, &req) {
+			return
+		}
+		if req.Key == "" {
+			writeError(w, http.StatusBadRequest, errors.New("key can not be empty"))
+			return
+		}
+
+		alphabetMap, power, err := alphabet(req.Alphabet)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		f, format := c.Encrypt, c.Plaintext
+		if !encryption {
+			f, format = c.Decrypt, c.Ciphertext
+		}
+		if f == nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("%s can only sign, it does not encrypt", c.ID))
+			return
+		}
+		// возведение в степень по огромному модулю не прерывается, поэтому такой ключ не принимается
+		if c.KeySize != nil && c.KeySize(req.Key) > maxKeyBits {
+			writeError(w, http.StatusUnprocessableEntity, fmt.Errorf("the server accepts keys of at most %d bits", maxKeyBits))
+			return
+		}
+		if format == registry.Alphabet {
+			if err := verify.Text(req.Text, alphabetMap); err != nil {
+				writeError(w, http.StatusUnprocessableEntity, err)
+				return
+			}
+		}
+
+		result, err := f(req.Text, req.Key, alphabetMap, power)
+		if err != nil {
+			writeError(w, http.StatusUnprocessableEntity, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, resultResponse{Result: result})
+	}
+}
+
+func (s *server) keygen(w http.ResponseWriter, r *http.Request) {
+	var req keygenRequest
+	if !s.decode(w, r, &req) {
+		return
+	}
+
+	c, ok := lookup(w, req.Cipher)
+	if !ok {
+		return
+	}
+	if c.NewKey == nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("%s has no key generation", c.ID))
+		return
+	}
+
+	alphabetMap, power, err := alphabet(req.Alphabet)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if bits, ok := registry.KeyBits(req.Params); ok && bits > maxKeyBits {
+		writeError(w, http.StatusUnprocessableEntity, fmt.Errorf("the server generates keys of at most %d bits", maxKeyBits))
+		return
+	}
+
+	select {
+	case s.keygens <- struct{}{}:
+		defer func() { <-s.keygens }()
+	default:
+		writeError(w, http.StatusServiceUnavailable, errors.New("too many keys are being generated, try again later"))
+		return
+	}
+
+	// поиск простых чисел прекращается, когда клиент отключился или истекло время запроса
+	key, generated, err := c.NewKey(r.Context(), req.Params, alphabetMap, power)
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+	if !generated {
+		writeError(w, http.StatusUnprocessableEntity, fmt.Errorf("params do not ask for a new key, the key format is: %s", c.KeyFormat))
+		return
+	}
+	writeJSON(w, http.StatusOK, keygenResponse{Key: key})
+}
+
+func (s *server) analyze(w http.ResponseWriter, r *http.Request) {
+	var req analyzeRequest
+	if !s.decode(w, r, &req) {
+		return
+	}
+
+	alphabetMap, power, err := alphabet(req.Alphabet)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if err := verify.Text(req.Text, alphabetMap); err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	counts, total := frequency.Count(req.Text, alphabetMap)
+	resp := analyzeResponse{
+		Length:      total,
+		Counts:      make(map[string]int),
+		Frequencies: make(map[string]float64),
+		Coincidence: frequency.IndexOfCoincidence(counts, total),
+		ChiSquared:  frequency.ChiSquared(counts, total, frequency.For(alphabetMap)),
+		CaesarShift: frequency.CaesarShift(req.Text, alphabetMap, power),
+	}
+	for char, n := range counts {
+		resp.Counts[string(char)] = n
+		resp.Frequencies[string(char)] = float64(n) / float64(total)
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// decode reads the JSON body into v, on failure it writes the error and returns false
+func (s *server) decode(w http.ResponseWriter, r *http.Request, v any) bool {
+	r.Body = http.MaxBytesReader(w, r.Body, s.maxBody)
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+
+	err := dec.Decode(v)
+	if err == nil && dec.More() {
+		err = errors.New("body must contain a single JSON object")
+	}
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		switch {
+		case errors.As(err, &tooLarge):
+			writeError(w, http.StatusRequestEntityTooLarge, fmt.Errorf("request body is larger than %d bytes", tooLarge.Limit))
+		case errors.Is(err, io.EOF):
+			writeError(w, http.StatusBadRequest, errors.New("request body is empty"))
+		default:
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid JSON: %v", err))
+		}
+		return false
+	}
+	return true
+}
+
+// lookup finds the cipher, on failure it writes the error and returns false
+func lookup(w http.ResponseWriter, id string) (registry.Cipher, bool) {
+	c, ok := registry.Lookup(id)
+	if !ok || c.Files {
+		writeError(w, http.StatusNotFound, fmt.Errorf("unknown cipher %q", id))
+		return registry.Cipher{}, false
+	}
+	return c, true
+}
+
+// alphabet checks the alphabet of the request and returns its map and power
+func alphabet(s string) (map[rune]int, int, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, 0, errors.New("alphabet can not be empty")
+	}
+	alphabetMap, err := verify.Alphabet(s)
+	if err != nil {
+		return nil, 0, err
+	}
+	return alphabetMap, utf8.RuneCountInString(s), nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("writing response: %v", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	resp := errorResponse{Error: err.Error()}
+	var invalid *encrypt.ErrInvalidRune
+	if errors.As(err, &invalid) {
+		resp.Position = &invalid.Pos
+	}
+	writeJSON(w, status, resp)
+}
@@ -0,0 +1,170 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const russian = "АБВГДЕЁЖЗИЙКЛМНОПРСТУФХЦЧШЩЪЫЬЭЮЯ "
+
+// do sends the request to the handler and decodes the JSON response into v when v is not nil
+func do(t *testing.T, h http.Handler, method, path, body string, v any) int {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(method, path, strings.NewReader(body)))
+	if v != nil {
+		if err := json.Unmarshal(rec.Body.Bytes(), v); err != nil {
+			t.Fatalf("%s %s: response %q: %v", method, path, rec.Body, err)
+		}
+	}
+	return rec.Code
+}
+
+func body(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	return string(data)
+}
+
+func TestCiphers(t *testing.T) {
+	var list []cipherInfo
+	if code := do(t, New(DefaultMaxBody), "GET", "/v1/ciphers", "", &list); code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", code)
+	}
+
+	ids := make(map[string]bool)
+	for _, c := range list {
+		ids[c.ID] = true
+	}
+	if !ids["caesar"] || !ids["rsa"] {
+		t.Errorf("ciphers %v do not include caesar and rsa", ids)
+	}
+	// шифры-блокноты читают файлы на диске сервера
+	for _, id := range []string{"otp", "xor"} {
+		if ids[id] {
+			t.Errorf("ciphers include %s, its key names files", id)
+		}
+	}
+}
+
+func TestCrypt(t *testing.T) {
+	h := New(DefaultMaxBody)
+
+	var result resultResponse
+	code := do(t, h, "POST", "/v1/caesar/encrypt", body(cipherRequest{Alphabet: russian, Key: "Г", Text: "АБВ"}), &result)
+	if code != http.StatusOK || result.Result != "ГДЕ" {
+		t.Errorf("encrypt: status %d, result %q, want 200 and %q", code, result.Result, "ГДЕ")
+	}
+	code = do(t, h, "POST", "/v1/caesar/decrypt", body(cipherRequest{Alphabet: russian, Key: "Г", Text: "ГДЕ"}), &result)
+	if code != http.StatusOK || result.Result != "АБВ" {
+		t.Errorf("decrypt: status %d, result %q, want 200 and %q", code, result.Result, "АБВ")
+	}
+
+	// ключ RSA в 2333 бита и ключ в миллион цифр отклоняются до разбора ключа
+	bigKey := "1" + strings.Repeat("0", 702) + "|65537"
+	hugeKey := "1" + strings.Repeat("0", 1000000) + "|65537"
+
+	tests := []struct {
+		name, path, body string
+		code             int
+		error            string // часть текста ошибки, когда она важна
+	}{
+		{"unknown cipher", "/v1/nope/encrypt", body(cipherRequest{Alphabet: russian, Key: "Г", Text: "А"}), http.StatusNotFound, ""},
+		{"pad cipher", "/v1/otp/encrypt", body(cipherRequest{Alphabet: russian, Key: "pad|0", Text: "А"}), http.StatusNotFound, ""},
+		{"invalid JSON", "/v1/caesar/encrypt", "{", http.StatusBadRequest, ""},
+		{"unknown field", "/v1/caesar/encrypt", `{"alphabet": "АБ", "key": "Б", "text": "А", "mode": "x"}`, http.StatusBadRequest, ""},
+		{"empty body", "/v1/caesar/encrypt", "", http.StatusBadRequest, ""},
+		{"empty key", "/v1/caesar/encrypt", body(cipherRequest{Alphabet: russian, Text: "А"}), http.StatusBadRequest, ""},
+		{"empty alphabet", "/v1/caesar/encrypt", body(cipherRequest{Key: "Г", Text: "А"}), http.StatusBadRequest, ""},
+		{"signature scheme", "/v1/gost3410/encrypt", body(cipherRequest{Alphabet: russian, Key: "x", Text: "А"}), http.StatusBadRequest, ""},
+		{"invalid key", "/v1/caesar/encrypt", body(cipherRequest{Alphabet: russian, Key: "ГД", Text: "А"}), http.StatusUnprocessableEntity, ""},
+		{"large key", "/v1/rsa/encrypt", body(cipherRequest{Alphabet: russian, Key: bigKey, Text: "А"}), http.StatusUnprocessableEntity, "at most 2048 bits"},
+		{"huge key", "/v1/rsa-oaep/decrypt", body(cipherRequest{Alphabet: russian, Key: hugeKey, Text: "00"}), http.StatusUnprocessableEntity, "at most 2048 bits"},
+	}
+	for _, tt := range tests {
+		var resp errorResponse
+		if code := do(t, h, "POST", tt.path, tt.body, &resp); code != tt.code || resp.Error == "" || !strings.Contains(resp.Error, tt.error) {
+			t.Errorf("%s: status %d, error %q, want %d and an error with %q", tt.name, code, resp.Error, tt.code, tt.error)
+		}
+	}
+}
+
+func TestInvalidRunePosition(t *testing.T) {
+	var resp errorResponse
+	code := do(t, New(DefaultMaxBody), "POST", "/v1/caesar/encrypt", body(cipherRequest{Alphabet: russian, Key: "Г", Text: "АБQ"}), &resp)
+	if code != http.StatusUnprocessableEntity {
+		t.Fatalf("status = %d, want 422", code)
+	}
+	if resp.Position == nil || *resp.Position != 2 {
+		t.Errorf("position = %v, want 2", resp.Position)
+	}
+}
+
+func TestBodyLimit(t *testing.T) {
+	h := New(64)
+	small := body(cipherRequest{Alphabet: "АБ", Key: "Б", Text: "А"})
+	if code := do(t, h, "POST", "/v1/caesar/encrypt", small, nil); code != http.StatusOK {
+		t.Errorf("small body: status = %d, want 200", code)
+	}
+
+	large := body(cipherRequest{Alphabet: "АБ", Key: "Б", Text: strings.Repeat("А", 100)})
+	var resp errorResponse
+	if code := do(t, h, "POST", "/v1/caesar/encrypt", large, &resp); code != http.StatusRequestEntityTooLarge {
+		t.Errorf("large body: status = %d, want 413", code)
+	}
+}
+
+func TestKeygen(t *testing.T) {
+	h := New(DefaultMaxBody)
+
+	var key keygenResponse
+	code := do(t, h, "POST", "/v1/keygen", body(keygenRequest{Cipher: "rsa", Alphabet: russian, Params: "new|512"}), &key)
+	if code != http.StatusOK || !strings.Contains(key.Key, "|") {
+		t.Errorf("rsa new|512: status %d, key %q, want 200 and n|e|d", code, key.Key)
+	}
+
+	tests := []struct {
+		name string
+		req  keygenRequest
+		code int
+	}{
+		{"unknown cipher", keygenRequest{Cipher: "nope", Alphabet: russian, Params: "new|512"}, http.StatusNotFound},
+		{"no key generation", keygenRequest{Cipher: "caesar", Alphabet: russian, Params: "new"}, http.StatusBadRequest},
+		{"key too large", keygenRequest{Cipher: "rsa", Alphabet: russian, Params: "new|4096"}, http.StatusUnprocessableEntity},
+		{"not a generation", keygenRequest{Cipher: "rsa", Alphabet: russian, Params: "3233|17|413"}, http.StatusUnprocessableEntity},
+	}
+	for _, tt := range tests {
+		var resp errorResponse
+		if code := do(t, h, "POST", "/v1/keygen", body(tt.req), &resp); code != tt.code {
+			t.Errorf("%s: status %d (%s), want %d", tt.name, code, resp.Error, tt.code)
+		}
+	}
+}
+
+func TestKeygenBusy(t *testing.T) {
+	s := &server{maxBody: DefaultMaxBody, keygens: make(chan struct{}, maxKeygens)}
+	// все места заняты идущими генерациями
+	for i := 0; i < maxKeygens; i++ {
+		s.keygens <- struct{}{}
+	}
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest("POST", "/v1/keygen", strings.NewReader(body(keygenRequest{Cipher: "rsa", Alphabet: russian, Params: "new|512"})))
+	s.keygen(rec, req)
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want 503", rec.Code)
+	}
+
+	<-s.keygens
+	rec = httptest.NewRecorder()
+	req = httptest.NewRequest("POST", "/v1/keygen", strings.NewReader(body(keygenRequest{Cipher: "rsa", Alphabet: russian, Params: "new|512"})))
+	s.keygen(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Errorf("status with a free slot = %d, want 200", rec.Code)
+	}
+}
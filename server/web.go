@@ -0,0 +1,34 @@
+package server
+
+import (
+	_ "embed"
+	"net/http"
+	"path/filepath"
+)
+
+// page loads cipher.wasm and calls the JavaScript API of the web package from the browser
+//
+//go:embed index.html
+var page []byte
+
+// pageFiles are served next to the page from the directory given to Page
+var pageFiles = []string{"cipher.wasm", "wasm_exec.js"}
+
+// Page serves the page at / and the files it loads from dir, they are made with
+//
+//	GOOS=js GOARCH=wasm go build -o dir/cipher.wasm ./web/wasm
+//	cp "$(go env GOROOT)/lib/wasm/wasm_exec.js" dir
+func Page(dir string) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write(page)
+	})
+	for _, name := range pageFiles {
+		path := filepath.Join(dir, name)
+		mux.HandleFunc("GET /"+name, func(w http.ResponseWriter, r *http.Request) {
+			http.ServeFile(w, r, path)
+		})
+	}
+	return mux
+}
@@ -0,0 +1,269 @@
+package stream
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Generator produces a keystream
+type Generator interface {
+	Next() int     // next output: a bit for LFSR and A5/1, a byte for RC4
+	Bits() int     // number of bits in one output
+	State() string // internal state, used to find the period
+}
+
+// Byte assembles the next keystream byte from the generator outputs, most significant bit first
+func Byte(g Generator) byte {
+	if g.Bits() == 8 {
+		return byte(g.Next())
+	}
+
+	var b byte
+	for i := 0; i < 8; i += g.Bits() {
+		b = b<<g.Bits() | byte(g.Next())
+	}
+	return b
+}
+
+// maxDraws limits the values skipped in a row by Index. A working generator is rejected
+// with probability below 1/2, so only a degenerate one reaches the limit
+const maxDraws = 128
+
+// Index returns the next keystream value in [0, power). Values that would make
+// the result unevenly distributed are skipped, alphabets of more than 256 symbols take several bytes per value
+func Index(g Generator, power int) (int, error) {
+	size := uint64(256)
+	for size < uint64(power) {
+		size *= 256
+	}
+	limit := size - size%uint64(power)
+
+	for i := 0; i < maxDraws; i++ {
+		var v uint64
+		for n := size; n > 1; n /= 256 {
+			v = v<<8 | uint64(Byte(g))
+		}
+		if v < limit {
+			return int(v % uint64(power)), nil
+		}
+	}
+	return 0, fmt.Errorf("keystream gave %d values out of range in a row, the generator is degenerate", maxDraws)
+}
+
+// Period counts the outputs until the generator returns to its current state.
+// It gives up after limit outputs, the generator is advanced in both cases
+func Period(g Generator, limit int) (int, bool) {
+	start := g.State()
+	for i := 1; i <= limit; i++ {
+		g.Next()
+		if g.State() == start {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// Kinds of linear feedback shift registers
+const (
+	Fibonacci = "fibonacci"
+	Galois    = "galois"
+)
+
+// LFSR is a linear feedback shift register of up to 64 bits. Taps are the exponents
+// of the feedback polynomial, e.g. 16, 14, 13, 11 for x^16 + x^14 + x^13 + x^11 + 1
+type LFSR struct {
+	kind   string
+	length int
+	mask   uint64
+	state  uint64
+}
+
+// NewLFSR creates the register, the seed is written most significant bit first
+func NewLFSR(kind string, taps []int, seed uint64) (*LFSR, error) {
+	if kind != Fibonacci && kind != Galois {
+		return nil, fmt.Errorf("lfsr must be %s or %s", Fibonacci, Galois)
+	}
+	if len(taps) == 0 {
+		return nil, fmt.Errorf("lfsr needs at least one tap")
+	}
+
+	length := 0
+	for _, t := range taps {
+		if t < 1 || t > 64 {
+			return nil, fmt.Errorf("lfsr taps must be between 1 and 64")
+		}
+		length = max(length, t)
+	}
+
+	if seed == 0 {
+		return nil, fmt.Errorf("lfsr seed can not be zero, the register would output only zeros")
+	}
+	if length < 64 && seed>>length != 0 {
+		return nil, fmt.Errorf("lfsr seed is longer than the register of %d bits", length)
+	}
+
+	l := &LFSR{kind: kind, length: length, state: seed}
+	for _, t := range taps {
+		if kind == Fibonacci {
+			l.mask |= 1 << (length - t) // член x^t снимается с бита length-t
+		} else {
+			l.mask |= 1 << (t - 1)
+		}
+	}
+
+	return l, nil
+}
+
+func (l *LFSR) Next() int {
+	out := int(l.state & 1)
+
+	if l.kind == Fibonacci {
+		feedback := uint64(0)
+		for bits := l.state & l.mask; bits != 0; bits &= bits - 1 {
+			feedback ^= 1
+		}
+		l.state = l.state>>1 | feedback<<(l.length-1)
+	} else {
+		l.state >>= 1
+		if out == 1 {
+			l.state ^= l.mask
+		}
+	}
+
+	return out
+}
+
+func (l *LFSR) Bits() int {
+	return 1
+}
+
+func (l *LFSR) State() string {
+	return strconv.FormatUint(l.state, 2)
+}
+
+// A5/1 registers: length, feedback taps, clocking bit
+var a51Registers = [3]struct {
+	length int
+	taps   uint32
+	clock  uint32
+}{
+	{19, 1<<13 | 1<<16 | 1<<17 | 1<<18, 1 << 8},
+	{22, 1<<20 | 1<<21, 1 << 10},
+	{23, 1<<7 | 1<<20 | 1<<21 | 1<<22, 1 << 10},
+}
+
+// A51 is the GSM A5/1 generator of three registers clocked by majority
+type A51 struct {
+	r [3]uint32
+}
+
+func parity(x uint32) uint32 {
+	var p uint32
+	for ; x != 0; x &= x - 1 {
+		p ^= 1
+	}
+	return p
+}
+
+func (a *A51) clockRegister(i int) {
+	reg := a51Registers[i]
+	a.r[i] = (a.r[i]<<1 | parity(a.r[i]&reg.taps)) & (1<<reg.length - 1)
+}
+
+// NewA51 loads the 64-bit session key and the 22-bit frame number and runs the 100 mixing cycles
+func NewA51(key uint64, frame uint32) *A51 {
+	a := &A51{}
+
+	// биты ключа берутся начиная с младшего бита первого байта, как в эталонной реализации
+	for i := 0; i < 64; i++ {
+		bit := uint32(key>>(56-8*(i/8)+i%8)) & 1
+		for r := range a.r {
+			a.clockRegister(r)
+			a.r[r] ^= bit
+		}
+	}
+	for i := 0; i < 22; i++ {
+		bit := (frame >> i) & 1
+		for r := range a.r {
+			a.clockRegister(r)
+			a.r[r] ^= bit
+		}
+	}
+	for i := 0; i < 100; i++ {
+		a.Next()
+	}
+
+	return a
+}
+
+// Next clocks the registers whose clocking bit agrees with the majority and returns the output bit
+func (a *A51) Next() int {
+	var bits [3]uint32
+	for i, reg := range a51Registers {
+		if a.r[i]&reg.clock != 0 {
+			bits[i] = 1
+		}
+	}
+	majority := bits[0]&bits[1] | bits[0]&bits[2] | bits[1]&bits[2]
+
+	for i := range a.r {
+		if bits[i] == majority {
+			a.clockRegister(i)
+		}
+	}
+
+	var out uint32
+	for i, reg := range a51Registers {
+		out ^= a.r[i] >> (reg.length - 1)
+	}
+	return int(out & 1)
+}
+
+func (a *A51) Bits() int {
+	return 1
+}
+
+func (a *A51) State() string {
+	return fmt.Sprintf("%05x:%06x:%06x", a.r[0], a.r[1], a.r[2])
+}
+
+// RC4 is the RC4 byte generator
+type RC4 struct {
+	s    [256]byte
+	i, j byte
+}
+
+// NewRC4 runs the key scheduling with a key of 1 to 256 bytes
+func NewRC4(key []byte) (*RC4, error) {
+	if len(key) < 1 || len(key) > 256 {
+		return nil, fmt.Errorf("rc4 key must be 1 to 256 bytes long")
+	}
+
+	r := &RC4{}
+	for i := range r.s {
+		r.s[i] = byte(i)
+	}
+
+	var j byte
+	for i := range r.s {
+		j += r.s[i] + key[i%len(key)]
+		r.s[i], r.s[j] = r.s[j], r.s[i]
+	}
+
+	return r, nil
+}
+
+func (r *RC4) Next() int {
+	r.i++
+	r.j += r.s[r.i]
+	r.s[r.i], r.s[r.j] = r.s[r.j], r.s[r.i]
+	return int(r.s[r.s[r.i]+r.s[r.j]])
+}
+
+func (r *RC4) Bits() int {
+	return 8
+}
+
+func (r *RC4) State() string {
+	return fmt.Sprintf("%x:%x:%x", r.i, r.j, r.s)
+}
@@ -0,0 +1,88 @@
+package stream
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func keystream(g Generator, n int) string {
+	out := make([]byte, n)
+	for i := range out {
+		out[i] = Byte(g)
+	}
+	return hex.EncodeToString(out)
+}
+
+// эталонный пример A5/1 (Briceno, Goldberg, Wagner): первые 14 байтов потока A->B
+func TestA51Vector(t *testing.T) {
+	got := keystream(NewA51(0x1223456789abcdef, 0x134), 14)
+	if want := "534eaa582fe8151ab6e1855a728c"; got != want {
+		t.Errorf("keystream = %s, want %s", got, want)
+	}
+}
+
+// примеры RC4 из статьи Википедии
+func TestRC4Vectors(t *testing.T) {
+	tests := []struct {
+		key, want string
+	}{
+		{"Key", "eb9f7781b734ca72a719"},
+		{"Wiki", "6044db6d41b7"},
+		{"Secret", "04d46b053ca87b59"},
+	}
+	for _, tt := range tests {
+		g, err := NewRC4([]byte(tt.key))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := keystream(g, len(tt.want)/2); got != tt.want {
+			t.Errorf("keystream of %q = %s, want %s", tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestLFSRPeriod(t *testing.T) {
+	// x^16 + x^14 + x^13 + x^11 + 1 примитивный, период 2^16 - 1
+	for _, kind := range []string{Fibonacci, Galois} {
+		g, err := NewLFSR(kind, []int{16, 14, 13, 11}, 0xace1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if period, ok := Period(g, 1<<16); !ok || period != 1<<16-1 {
+			t.Errorf("%s period = %d, %v, want %d", kind, period, ok, 1<<16-1)
+		}
+	}
+}
+
+// constant is a degenerate generator that always outputs the same byte
+type constant byte
+
+func (c constant) Next() int     { return int(c) }
+func (c constant) Bits() int     { return 8 }
+func (c constant) State() string { return "" }
+
+func TestIndex(t *testing.T) {
+	// 0xff отбрасывается для 34 символов, вырожденный генератор не должен зациклиться
+	if _, err := Index(constant(0xff), 34); err == nil {
+		t.Error("Index accepted a generator that outputs only 0xff")
+	}
+
+	if got, err := Index(constant(0x10), 34); err != nil || got != 0x10%34 {
+		t.Errorf("Index = %d, %v, want %d", got, err, 0x10%34)
+	}
+
+	// для 300 символов значение собирается из двух байтов
+	g, err := NewRC4([]byte("Key"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 1000; i++ {
+		v, err := Index(g, 300)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v < 0 || v >= 300 {
+			t.Fatalf("Index = %d, want a value in [0, 300)", v)
+		}
+	}
+}
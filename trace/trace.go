@@ -0,0 +1,96 @@
+package trace
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"text/tabwriter"
+)
+
+// Step is one step of a cipher: a symbol, a pair or a table row transformed by a formula
+type Step struct {
+	Pos     int    // position of the symbol in the text starting from 1, 0 for steps on the key or the whole table
+	Input   string // what is transformed: a symbol, a pair of symbols, a table row or a key value
+	Formula string // the formula in terms of the key, e.g. (K1*idx+K2) % power
+	Values  string // the same formula with the numbers substituted
+	Output  string // the result of the step
+}
+
+// Tracer receives the steps of the cipher
+type Tracer interface {
+	Step(s Step)
+}
+
+// TracerFunc adapts a function to the Tracer interface
+type TracerFunc func(s Step)
+
+func (f TracerFunc) Step(s Step) {
+	f(s)
+}
+
+var (
+	mu     sync.RWMutex
+	tracer Tracer
+)
+
+// SetTracer sets the tracer the ciphers report to, nil turns tracing off
+func SetTracer(t Tracer) {
+	mu.Lock()
+	defer mu.Unlock()
+	tracer = t
+}
+
+// Enabled reports whether a tracer is set, ciphers check it before formatting the step values
+func Enabled() bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	return tracer != nil
+}
+
+// Emit passes the step to the tracer, if there is one
+func Emit(s Step) {
+	mu.RLock()
+	t := tracer
+	mu.RUnlock()
+
+	if t != nil {
+		t.Step(s)
+	}
+}
+
+// Table collects the steps to render them as a table
+type Table struct {
+	Steps []Step
+}
+
+func (t *Table) Step(s Step) {
+	t.Steps = append(t.Steps, s)
+}
+
+// Reset drops the collected steps
+func (t *Table) Reset() {
+	t.Steps = nil
+}
+
+// Render writes the steps as a table with aligned columns
+func (t *Table) Render(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "#\tinput\tformula\tvalues\toutput")
+	for _, s := range t.Steps {
+		pos := "-"
+		if s.Pos > 0 {
+			pos = fmt.Sprint(s.Pos)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", pos, quote(s.Input), s.Formula, s.Values, quote(s.Output))
+	}
+	return tw.Flush()
+}
+
+// quote makes leading and trailing spaces and empty values visible in the table
+func quote(s string) string {
+	if s == "" || strings.TrimSpace(s) != s {
+		return fmt.Sprintf("%q", s)
+	}
+	return s
+}
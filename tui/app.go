@@ -0,0 +1,324 @@
+// Package tui is the full-screen terminal interface of the program: a list of ciphers,
+// editors of the alphabet, the key and the text checked as they are typed, and a preview of the result
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/marelinaa/cipher-algorithms/encrypt"
+	"github.com/marelinaa/cipher-algorithms/registry"
+	"github.com/marelinaa/cipher-algorithms/verify"
+)
+
+// ErrNoTerminal is returned by Run when the standard input or output is not a terminal
+var ErrNoTerminal = errors.New("the program is not run in a terminal")
+
+// Config holds the initial values of the editors and the files the results are saved to
+type Config struct {
+	Alphabet string
+	Key      string
+	Text     string
+
+	KeyFile     string // a generated key is saved here
+	EncryptFile string
+	DecryptFile string
+}
+
+// Run shows the interface until the user quits
+func Run(cfg Config) error {
+	in, out := int(os.Stdin.Fd()), os.Stdout
+	if _, _, err := size(int(out.Fd())); err != nil {
+		return err
+	}
+	restore, err := makeRaw(in)
+	if err != nil {
+		return err
+	}
+	defer restore()
+
+	io.WriteString(out, "\x1b[?1049h")
+	defer io.WriteString(out, "\x1b[?25h\x1b[?1049l")
+
+	a := newApp(cfg)
+	buf := make([]byte, 4096)
+	for {
+		w, h, err := size(int(out.Fd()))
+		if err != nil {
+			return err
+		}
+		io.WriteString(out, a.draw(w, h))
+
+		n, err := os.Stdin.Read(buf)
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		for _, k := range parseKeys(buf[:n]) {
+			if !a.handle(k) {
+				return nil
+			}
+		}
+	}
+}
+
+// pane is the part of the screen that receives the keys
+type pane int
+
+const (
+	paneCiphers pane = iota
+	paneAlphabet
+	paneKey
+	paneText
+	paneCount
+)
+
+type app struct {
+	cfg     Config
+	ciphers []registry.Cipher
+
+	selected   int
+	focus      pane
+	decryption bool
+
+	alphabet, key, text field
+
+	// результаты проверки, пересчитываются после каждого изменения
+	alphabetMap map[rune]int
+	power       int
+	alphabetErr error
+	keyErr      error
+	textErr     error
+	preview     string
+	note        string // пояснение, когда предпросмотр не выполняется
+
+	result     string // результат последней выполненной операции
+	resultFile string // файл, в который он сохраняется
+	history    []string
+	status     string
+}
+
+func newApp(cfg Config) *app {
+	a := &app{cfg: cfg}
+	// signature schemes are used through the sign and verify commands
+	for _, c := range registry.All() {
+		if c.Encrypt != nil {
+			a.ciphers = append(a.ciphers, c)
+		}
+	}
+	a.alphabet.set(cfg.Alphabet)
+	a.key.set(cfg.Key)
+	a.text.set(cfg.Text)
+	a.validate()
+	return a
+}
+
+func (a *app) cipher() registry.Cipher {
+	return a.ciphers[a.selected]
+}
+
+func (a *app) operation() string {
+	if a.decryption {
+		return "Decryption"
+	}
+	return "Encryption"
+}
+
+// handle applies the key and returns false when the user quits
+func (a *app) handle(k Key) bool {
+	switch {
+	case k.Code == KeyEsc, k.Code == KeyCtrl && (k.Rune == 'c' || k.Rune == 'q'):
+		return false
+	case k.Code == KeyTab:
+		a.focus = (a.focus + 1) % paneCount
+	case k.Code == KeyBacktab:
+		a.focus = (a.focus + paneCount - 1) % paneCount
+	case k.Code == KeyEnter:
+		a.run()
+	case k.Code == KeyCtrl && k.Rune == 't':
+		a.decryption = !a.decryption
+		a.validate()
+	case k.Code == KeyCtrl && k.Rune == 's':
+		a.save()
+	case k.Code == KeyCtrl && k.Rune == 'r':
+		// результат становится входным текстом обратной операции
+		if a.result != "" {
+			a.text.set(a.result)
+			a.decryption = !a.decryption
+			a.validate()
+		}
+	case a.focus == paneCiphers:
+		a.moveSelection(k)
+	default:
+		f := map[pane]*field{paneAlphabet: &a.alphabet, paneKey: &a.key, paneText: &a.text}[a.focus]
+		if f.handle(k) {
+			a.validate()
+		}
+	}
+	return true
+}
+
+func (a *app) moveSelection(k Key) {
+	old := a.selected
+	switch k.Code {
+	case KeyUp:
+		a.selected = max(a.selected-1, 0)
+	case KeyDown:
+		a.selected = min(a.selected+1, len(a.ciphers)-1)
+	case KeyHome:
+		a.selected = 0
+	case KeyEnd:
+		a.selected = len(a.ciphers) - 1
+	}
+	if a.selected != old {
+		a.validate()
+	}
+}
+
+// validate checks the alphabet, the key and the text and computes the preview
+func (a *app) validate() {
+	a.alphabetErr, a.keyErr, a.textErr = nil, nil, nil
+	a.preview, a.note = "", ""
+
+	alphabet := a.alphabet.String()
+	a.alphabetMap, a.power = nil, utf8.RuneCountInString(alphabet)
+	if alphabet == "" {
+		a.alphabetErr = errors.New("alphabet can not be empty")
+		return
+	}
+	alphabetMap, err := verify.Alphabet(alphabet)
+	if err != nil {
+		a.alphabetErr = err
+		return
+	}
+	a.alphabetMap = alphabetMap
+
+	c := a.cipher()
+	f, format := c.Encrypt, c.Plaintext
+	if a.decryption {
+		f, format = c.Decrypt, c.Ciphertext
+	}
+	if format == registry.Alphabet {
+		a.textErr = verify.Text(a.text.String(), a.alphabetMap)
+	}
+
+	key := a.key.String()
+	switch {
+	case key == "":
+		a.keyErr = errors.New("key can not be empty")
+		return
+	case c.Files:
+		// ключ называет файл блокнота, операция читает и дописывает его, поэтому выполняется только по Enter
+		a.note = "the key names pad files, press Enter to run the operation"
+		return
+	case c.NewKey != nil && strings.HasPrefix(key, "new|"):
+		a.note = "a new key is generated when the operation runs"
+		return
+	case c.NewKey != nil:
+		if _, generated, err := c.NewKey(context.Background(), key, a.alphabetMap, a.power); err != nil {
+			a.keyErr = err
+			return
+		} else if generated {
+			a.note = "a new key is generated when the operation runs"
+			return
+		}
+	}
+	if a.textErr != nil {
+		return
+	}
+
+	a.preview, err = call(f, a.text.String(), key, a.alphabetMap, a.power)
+	if err != nil {
+		var invalid *encrypt.ErrInvalidRune
+		if errors.As(err, &invalid) {
+			a.textErr = err
+		} else {
+			a.keyErr = err
+		}
+	}
+}
+
+// call runs the cipher, a key the cipher does not check must not close the interface with a panic
+func call(f registry.Func, input, key string, alphabetMap map[rune]int, power int) (result string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("cipher failed: %v", r)
+		}
+	}()
+	return f(input, key, alphabetMap, power)
+}
+
+// run performs the operation, generating the key first when the key asks for it
+func (a *app) run() {
+	if a.alphabetErr != nil || a.textErr != nil {
+		a.status = "fix the errors before running the operation"
+		return
+	}
+	c := a.cipher()
+	key := a.key.String()
+	if key == "" {
+		a.status = "key can not be empty"
+		return
+	}
+
+	if c.NewKey != nil {
+		generated, ok, err := c.NewKey(context.Background(), key, a.alphabetMap, a.power)
+		if err != nil {
+			a.status = err.Error()
+			return
+		}
+		if ok {
+			key = generated
+			a.key.set(key)
+			if err := os.WriteFile(a.cfg.KeyFile, []byte(key), 0644); err != nil {
+				a.status = err.Error()
+				return
+			}
+			a.addHistory(fmt.Sprintf("%s: generated key saved to %s", c.ID, a.cfg.KeyFile))
+		}
+	}
+
+	f := c.Encrypt
+	if a.decryption {
+		f = c.Decrypt
+	}
+	result, err := call(f, a.text.String(), key, a.alphabetMap, a.power)
+	if err != nil {
+		a.status = err.Error()
+		a.addHistory(fmt.Sprintf("%s %s failed: %v", c.ID, strings.ToLower(a.operation()), err))
+		return
+	}
+	a.result, a.resultFile = result, a.cfg.EncryptFile
+	if a.decryption {
+		a.resultFile = a.cfg.DecryptFile
+	}
+	a.status = fmt.Sprintf("%s done, Ctrl-S saves the result", a.operation())
+	a.addHistory(fmt.Sprintf("%s %s: %s -> %s", c.ID, strings.ToLower(a.operation()), a.text.String(), result))
+	a.validate()
+}
+
+// save writes the result of the last operation to the file of its operation
+func (a *app) save() {
+	if a.result == "" {
+		a.status = "nothing to save, press Enter to run the operation"
+		return
+	}
+	if err := os.WriteFile(a.resultFile, []byte(a.result), 0644); err != nil {
+		a.status = err.Error()
+		return
+	}
+	a.status = "result saved to " + a.resultFile
+	a.addHistory(a.status)
+}
+
+func (a *app) addHistory(s string) {
+	a.history = append(a.history, time.Now().Format("15:04:05")+" "+s)
+}
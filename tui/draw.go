@@ -0,0 +1,139 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	listWidth = 18 // ширина колонки со списком шифров
+
+	styleReset    = "\x1b[0m"
+	styleBold     = "\x1b[1m"
+	styleReverse  = "\x1b[7m"
+	styleDim      = "\x1b[2m"
+	styleError    = "\x1b[31m"
+	styleOK       = "\x1b[32m"
+	styleFocused  = "\x1b[1;36m"
+	hideCursor    = "\x1b[?25l"
+	showCursor    = "\x1b[?25h"
+	clearScreen   = "\x1b[H\x1b[2J"
+	minimalWidth  = listWidth + 30
+	minimalHeight = 16
+)
+
+// screen collects one frame of the interface
+type screen struct {
+	w, h int
+	buf  strings.Builder
+}
+
+// put writes the text at the row and the column (both from 0), cutting it at the right edge
+func (s *screen) put(row, col int, style, text string) {
+	if row < 0 || row >= s.h || col >= s.w {
+		return
+	}
+	runes := []rune(text)
+	if len(runes) > s.w-col {
+		runes = runes[:s.w-col]
+	}
+	fmt.Fprintf(&s.buf, "\x1b[%d;%dH%s%s%s", row+1, col+1, style, string(runes), styleReset)
+}
+
+// draw returns the escape sequences that paint the whole interface
+func (a *app) draw(w, h int) string {
+	s := &screen{w: w, h: h}
+	s.buf.WriteString(hideCursor + clearScreen)
+	if w < minimalWidth || h < minimalHeight {
+		s.put(0, 0, styleError, fmt.Sprintf("the terminal must be at least %dx%d", minimalWidth, minimalHeight))
+		return s.buf.String()
+	}
+
+	c := a.cipher()
+	s.put(0, 0, styleBold, fmt.Sprintf(" %s: %s", a.operation(), c.Name))
+
+	// список шифров, прокручивается так, чтобы выбранный был виден
+	rows := h - 3
+	s.put(1, 0, a.title(paneCiphers), "Ciphers")
+	top := max(0, a.selected-rows+2)
+	for i := top; i < len(a.ciphers) && i-top < rows-1; i++ {
+		style := ""
+		if i == a.selected {
+			style = styleReverse
+		}
+		s.put(2+i-top, 0, style, " "+a.ciphers[i].ID+" ")
+	}
+
+	col, width := listWidth, w-listWidth-1
+	cursorRow, cursorCol := -1, 0
+	row := 1
+	for _, p := range []struct {
+		pane  pane
+		title string
+		field *field
+		hint  string
+		err   error
+	}{
+		{paneAlphabet, "Alphabet", &a.alphabet, fmt.Sprintf("power %d", a.power), a.alphabetErr},
+		{paneKey, "Key", &a.key, "format: " + c.KeyFormat, a.keyErr},
+		{paneText, a.inputTitle(), &a.text, fmt.Sprintf("%d symbols", len(a.text.text)), a.textErr},
+	} {
+		s.put(row, col, a.title(p.pane), p.title)
+		text, cursor := p.field.view(width - 2)
+		s.put(row+1, col, "", "> "+text)
+		if a.focus == p.pane {
+			cursorRow, cursorCol = row+1, col+2+cursor
+		}
+		if p.err != nil {
+			s.put(row+2, col, styleError, p.err.Error())
+		} else {
+			s.put(row+2, col, styleDim, p.hint)
+		}
+		row += 4
+	}
+
+	s.put(row, col, styleBold, a.outputTitle())
+	switch {
+	case a.note != "":
+		s.put(row+1, col, styleDim, a.note)
+	case a.alphabetErr == nil && a.keyErr == nil && a.textErr == nil:
+		s.put(row+1, col, styleOK, a.preview)
+	}
+	row += 3
+
+	s.put(row, col, styleBold, "History")
+	for i := len(a.history) - 1; i >= 0 && row+1 < h-2; i-- {
+		row++
+		s.put(row, col, "", a.history[i])
+	}
+
+	s.put(h-2, 0, "", a.status)
+	s.put(h-1, 0, styleReverse, " Tab pane  ↑↓ cipher  Enter run  Ctrl-T encrypt/decrypt  Ctrl-S save  Ctrl-R result to text  Esc quit ")
+
+	if cursorRow >= 0 {
+		fmt.Fprintf(&s.buf, "\x1b[%d;%dH%s", cursorRow+1, cursorCol+1, showCursor)
+	}
+	return s.buf.String()
+}
+
+// title returns the style of the pane title, the focused pane is highlighted
+func (a *app) title(p pane) string {
+	if a.focus == p {
+		return styleFocused
+	}
+	return styleBold
+}
+
+func (a *app) inputTitle() string {
+	if a.decryption {
+		return fmt.Sprintf("Ciphertext (%s)", a.cipher().Ciphertext)
+	}
+	return fmt.Sprintf("Plaintext (%s)", a.cipher().Plaintext)
+}
+
+func (a *app) outputTitle() string {
+	if a.decryption {
+		return fmt.Sprintf("Plaintext preview (%s)", a.cipher().Plaintext)
+	}
+	return fmt.Sprintf("Ciphertext preview (%s)", a.cipher().Ciphertext)
+}
@@ -0,0 +1,73 @@
+package tui
+
+// field is a one-line text editor, the cursor is an index in the runes of the text
+type field struct {
+	text   []rune
+	cursor int
+}
+
+func (f *field) String() string {
+	return string(f.text)
+}
+
+// set replaces the text and moves the cursor to its end
+func (f *field) set(s string) {
+	f.text = []rune(s)
+	f.cursor = len(f.text)
+}
+
+// handle applies the key to the field and reports whether the text has changed
+func (f *field) handle(k Key) bool {
+	switch k.Code {
+	case KeyRune:
+		f.text = append(f.text[:f.cursor], append([]rune{k.Rune}, f.text[f.cursor:]...)...)
+		f.cursor++
+		return true
+	case KeyBackspace:
+		if f.cursor == 0 {
+			return false
+		}
+		f.text = append(f.text[:f.cursor-1], f.text[f.cursor:]...)
+		f.cursor--
+		return true
+	case KeyDelete:
+		if f.cursor == len(f.text) {
+			return false
+		}
+		f.text = append(f.text[:f.cursor], f.text[f.cursor+1:]...)
+		return true
+	case KeyLeft:
+		if f.cursor > 0 {
+			f.cursor--
+		}
+	case KeyRight:
+		if f.cursor < len(f.text) {
+			f.cursor++
+		}
+	case KeyHome:
+		f.cursor = 0
+	case KeyEnd:
+		f.cursor = len(f.text)
+	case KeyCtrl:
+		// Ctrl-U очищает поле, как в оболочке
+		if k.Rune == 'u' && len(f.text) > 0 {
+			f.set("")
+			return true
+		}
+	}
+	return false
+}
+
+// view returns the part of the text that fits in width columns with the cursor visible,
+// and the column of the cursor in that part
+func (f *field) view(width int) (string, int) {
+	if width <= 0 {
+		return "", 0
+	}
+	start := 0
+	if f.cursor >= width {
+		start = f.cursor - width + 1
+	}
+	end := min(start+width, len(f.text))
+	return string(f.text[start:end]), f.cursor - start
+}
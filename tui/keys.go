@@ -0,0 +1,113 @@
+package tui
+
+import "unicode/utf8"
+
+// Code is the kind of a pressed key
+type Code int
+
+const (
+	KeyRune Code = iota // a printable symbol in Key.Rune
+	KeyCtrl             // Ctrl with the letter in Key.Rune
+	KeyEnter
+	KeyBackspace
+	KeyDelete
+	KeyTab
+	KeyBacktab
+	KeyUp
+	KeyDown
+	KeyLeft
+	KeyRight
+	KeyHome
+	KeyEnd
+	KeyEsc
+)
+
+// Key is a key pressed in the terminal
+type Key struct {
+	Code Code
+	Rune rune
+}
+
+// csi maps the final byte of the escape sequences ESC [ x and ESC O x to keys
+var csi = map[byte]Code{
+	'A': KeyUp,
+	'B': KeyDown,
+	'C': KeyRight,
+	'D': KeyLeft,
+	'H': KeyHome,
+	'F': KeyEnd,
+	'Z': KeyBacktab,
+}
+
+// tilde maps the numbers of the sequences ESC [ n ~ to keys
+var tilde = map[string]Code{
+	"1": KeyHome,
+	"3": KeyDelete,
+	"4": KeyEnd,
+	"7": KeyHome,
+	"8": KeyEnd,
+}
+
+// parseKeys splits the bytes read from the terminal into keys, one read may hold several keys when text is pasted
+func parseKeys(buf []byte) []Key {
+	var keys []Key
+	for len(buf) > 0 {
+		key, n := parseKey(buf)
+		buf = buf[n:]
+		if n > 0 && key != (Key{}) {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// parseKey reads one key from the start of buf and returns the number of bytes it took
+func parseKey(buf []byte) (Key, int) {
+	switch b := buf[0]; {
+	case b == 0x1b:
+		return parseEscape(buf)
+	case b == '\r' || b == '\n':
+		return Key{Code: KeyEnter}, 1
+	case b == '\t':
+		return Key{Code: KeyTab}, 1
+	case b == 0x7f || b == 0x08:
+		return Key{Code: KeyBackspace}, 1
+	case b < 0x20:
+		// Ctrl-A ... Ctrl-Z приходят как байты 1 ... 26
+		return Key{Code: KeyCtrl, Rune: rune('a' + b - 1)}, 1
+	}
+
+	r, n := utf8.DecodeRune(buf)
+	if r == utf8.RuneError {
+		return Key{}, n
+	}
+	return Key{Code: KeyRune, Rune: r}, n
+}
+
+func parseEscape(buf []byte) (Key, int) {
+	if len(buf) == 1 {
+		return Key{Code: KeyEsc}, 1
+	}
+	if buf[1] != '[' && buf[1] != 'O' {
+		// Alt с клавишей не используется
+		return Key{Code: KeyEsc}, 1
+	}
+
+	// параметры последовательности - цифры и ';', затем завершающий байт
+	i := 2
+	for i < len(buf) && (buf[i] >= '0' && buf[i] <= '9' || buf[i] == ';') {
+		i++
+	}
+	if i == len(buf) {
+		return Key{}, len(buf)
+	}
+
+	params, final := string(buf[2:i]), buf[i]
+	if final == '~' {
+		return Key{Code: tilde[params]}, i + 1
+	}
+	if code, ok := csi[final]; ok {
+		return Key{Code: code}, i + 1
+	}
+	return Key{}, i + 1
+}
@@ -0,0 +1,33 @@
+package tui
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// ReadPassword prints the prompt to the standard error and reads a line from the standard input.
+// On a terminal the typed symbols are not shown, piped input is read as it is
+func ReadPassword(prompt string) (string, error) {
+	fmt.Fprint(os.Stderr, prompt)
+	restore, err := noEcho(int(os.Stdin.Fd()))
+	switch {
+	case err == nil:
+		defer func() {
+			restore()
+			// перевод строки, набранный пользователем, не был показан
+			fmt.Fprintln(os.Stderr)
+		}()
+	case !errors.Is(err, ErrNoTerminal):
+		return "", err
+	}
+
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
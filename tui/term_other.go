@@ -0,0 +1,20 @@
+//go:build !linux
+
+package tui
+
+import (
+	"fmt"
+	"runtime"
+)
+
+func makeRaw(fd int) (func(), error) {
+	return nil, fmt.Errorf("%w: the terminal interface is not supported on %s", ErrNoTerminal, runtime.GOOS)
+}
+
+func noEcho(fd int) (func(), error) {
+	return nil, fmt.Errorf("%w: hiding the input is not supported on %s", ErrNoTerminal, runtime.GOOS)
+}
+
+func size(fd int) (int, int, error) {
+	return 0, 0, fmt.Errorf("%w: the terminal interface is not supported on %s", ErrNoTerminal, runtime.GOOS)
+}
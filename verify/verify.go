@@ -1,13 +1,25 @@
 package verify
 
 import (
-	"errors"
+	"encoding/hex"
 	"fmt"
-	"math"
+	"math/big"
+	"strconv"
+	"strings"
 	"unicode"
 	"unicode/utf8"
 
+	"github.com/marelinaa/cipher-algorithms/asymmetric"
+	"github.com/marelinaa/cipher-algorithms/asymmetric/elgamal"
+	"github.com/marelinaa/cipher-algorithms/asymmetric/rsa"
+	"github.com/marelinaa/cipher-algorithms/asymmetric/shamir"
+	"github.com/marelinaa/cipher-algorithms/ecc"
+	"github.com/marelinaa/cipher-algorithms/encrypt"
+	"github.com/marelinaa/cipher-algorithms/enigma"
+	"github.com/marelinaa/cipher-algorithms/keygen"
 	"github.com/marelinaa/cipher-algorithms/keys"
+	"github.com/marelinaa/cipher-algorithms/modmath"
+	"github.com/marelinaa/cipher-algorithms/stream"
 )
 
 // Alphabet checks the alphabet for accuracy.
@@ -37,20 +49,14 @@ func Text(text string, alphabetMap map[rune]int) error {
 		return fmt.Errorf("text can not be empty")
 	}
 
-	for _, char := range text {
-		_, ok := alphabetMap[char] // check if the text contains allowable characters
-		if !ok {
-			return fmt.Errorf("text contains characters not from the alphabet")
-		}
-	}
-
-	return nil
+	// check if the text contains allowable characters
+	return encrypt.CheckText(text, alphabetMap)
 }
 
 func CaesarKey(key string, alphabetMap map[rune]int) (int, error) {
 	keyRunes := []rune(key)
 	if len(keyRunes) != 1 {
-		return -1, fmt.Errorf("caesar key must contain one symbol from the alphabet")
+		return -1, fmt.Errorf("%w: caesar key must contain one symbol from the alphabet", encrypt.ErrKeyLength)
 	}
 
 	k, ok := alphabetMap[keyRunes[0]]
@@ -65,7 +71,7 @@ func AffineKey(key string, alphabetMap map[rune]int, power int) (keys.Affine, er
 	err := "affine key must be a pair of symbols from the alphabet, without delimiters"
 	keyRunes := []rune(key)
 	if len(keyRunes) != 2 {
-		return keys.Affine{}, fmt.Errorf(err)
+		return keys.Affine{}, fmt.Errorf("%w: %s", encrypt.ErrKeyLength, err)
 	}
 
 	k1, ok := alphabetMap[keyRunes[0]]
@@ -77,10 +83,8 @@ func AffineKey(key string, alphabetMap map[rune]int, power int) (keys.Affine, er
 		return keys.Affine{}, fmt.Errorf(err)
 	}
 
-	fmt.Println(k1, k2)
-
-	if !areCoprime(k1, power) {
-		return keys.Affine{}, fmt.Errorf("numeric representations of symbols must be coprime")
+	if !modmath.Coprime(k1, power) {
+		return keys.Affine{}, fmt.Errorf("%w: numeric representation of the first symbol must be coprime with the power", encrypt.ErrNotInvertible)
 	}
 
 	affineKey := keys.Affine{
@@ -96,7 +100,7 @@ func SubstitutionKey(key string, alphabetMap map[rune]int, power int) ([]int, er
 	seen := make(map[rune]bool) // to track repeated characters
 	// Ensure key has the same length as the alphabet
 	if utf8.RuneCountInString(key) != power {
-		return nil, fmt.Errorf("key must be the same length as the alphabet")
+		return nil, fmt.Errorf("%w: key must be the same length as the alphabet", encrypt.ErrKeyLength)
 	}
 
 	for _, r := range key {
@@ -119,34 +123,6 @@ func SubstitutionKey(key string, alphabetMap map[rune]int, power int) ([]int, er
 	return keyInt, nil
 }
 
-func gcd(a, b int) int {
-	// Работаем с абсолютными значениями
-	a = int(math.Abs(float64(a)))
-	b = int(math.Abs(float64(b)))
-
-	if a == 0 {
-		return b
-	}
-
-	if b == 0 {
-		return a
-	}
-
-	for a != b {
-		if a > b {
-			a -= b
-		} else {
-			b -= a
-		}
-	}
-
-	return a
-}
-
-func areCoprime(a, b int) bool {
-	return gcd(a, b) == 1
-}
-
 func IsControl(r rune) bool {
 	cs := []rune{'\a', '\b', '\f', '\n', '\r', '\t', '\v'}
 
@@ -174,28 +150,15 @@ func determinant2x2(key [2][2]int) int {
 	k21 := key[1][0]
 	k22 := key[1][1]
 
-	fmt.Println(k11, k12, k21, k22)
-
 	return (k11*k22 - k12*k21)
 }
 
-func Mod(x, y int) int {
-	if x < 0 {
-		a := -x / y
-		fmt.Println(a)
-
-		return ((a)+1)*y + x
-	}
-
-	return x - (x/y)*y
-}
-
 func HillKey(keyString string, alphabetMap map[rune]int, power int) ([2][2]int, error) {
 	var key [2][2]int
 	var keyNum []int
 
 	if utf8.RuneCountInString(keyString) != 4 {
-		return [2][2]int{}, fmt.Errorf("key must contain 4 symbols from the alphabet")
+		return [2][2]int{}, fmt.Errorf("%w: key must contain 4 symbols from the alphabet", encrypt.ErrKeyLength)
 	}
 
 	for _, r := range keyString {
@@ -217,38 +180,19 @@ func HillKey(keyString string, alphabetMap map[rune]int, power int) ([2][2]int,
 
 	det := determinant2x2(key)
 	if det == 0 {
-		return [2][2]int{}, errors.New("matrix determinant is zero, key is not invertible")
+		return [2][2]int{}, fmt.Errorf("%w: matrix determinant is zero", encrypt.ErrNotInvertible)
 	}
 
-	if !areCoprime(det, power) {
-		return [2][2]int{}, errors.New("matrix determinant must be coprime with power of the alphabet, key is not invertible")
+	if !modmath.Coprime(det, power) {
+		return [2][2]int{}, fmt.Errorf("%w: matrix determinant must be coprime with power of the alphabet", encrypt.ErrNotInvertible)
 	}
 
-	// if Mod(det, power) != 1 {
-	// 	return [2][2]int{}, errors.New("matrix determinant must be coprime with power of the alphabet, key is not invertible")
-	// }
-
-	// _, err := modInverse(det, power)
-	// if err != nil {
-	// 	return errors.New("key is not invertible")
-	// }
 	return key, nil
 }
 
-func k1Inverse(k1, M int) int {
-	k1 = k1 % M
-	for i := 1; i < M; i++ {
-		if (k1*i)%M == 1 {
-			return i
-		}
-	}
-
-	return -1
-}
-
 func PermutationKey(keyString string, alphabetMap map[rune]int, power int) error {
 	if utf8.RuneCountInString(keyString) > power {
-		return fmt.Errorf("key length exceeds the maximum allowed length of %d", power)
+		return fmt.Errorf("%w: key length exceeds the maximum allowed length of %d", encrypt.ErrKeyLength, power)
 	}
 	seen := make(map[rune]bool)
 
@@ -275,3 +219,603 @@ func VigenereKey(keyString string, alphabetMap map[rune]int, power int) error {
 
 	return nil
 }
+
+func MyszkowskiKey(keyString string, alphabetMap map[rune]int) error {
+	if utf8.RuneCountInString(keyString) < 2 {
+		return fmt.Errorf("%w: myszkowski key must contain at least 2 symbols", encrypt.ErrKeyLength)
+	}
+
+	distinct := make(map[rune]bool)
+	for _, char := range keyString {
+		if _, ok := alphabetMap[char]; !ok {
+			return fmt.Errorf("key contains invalid symbol: '%c'", char)
+		}
+		distinct[char] = true
+	}
+
+	// ключ из одной повторяющейся буквы оставляет текст без изменений
+	if len(distinct) == 1 {
+		return fmt.Errorf("myszkowski key must contain at least 2 different symbols")
+	}
+
+	return nil
+}
+
+func DoubleTranspositionKey(keyString string, alphabetMap map[rune]int, power int) (keys.DoubleTransposition, error) {
+	parts := strings.Split(keyString, keys.Delimiter)
+	if len(parts) != 2 {
+		return keys.DoubleTransposition{}, fmt.Errorf("double transposition key must contain two keywords separated by '%s'", keys.Delimiter)
+	}
+
+	for _, part := range parts {
+		if part == "" {
+			return keys.DoubleTransposition{}, fmt.Errorf("double transposition keywords can not be empty")
+		}
+
+		err := PermutationKey(part, alphabetMap, power)
+		if err != nil {
+			return keys.DoubleTransposition{}, err
+		}
+	}
+
+	key := keys.DoubleTransposition{
+		First:  parts[0],
+		Second: parts[1],
+	}
+
+	return key, nil
+}
+
+func EnigmaKey(keyString string, alphabetMap map[rune]int, power int) (keys.Enigma, error) {
+	parts := strings.Split(keyString, keys.Delimiter)
+	if len(parts) != 5 && len(parts) != 6 {
+		return keys.Enigma{}, fmt.Errorf("enigma key must look like model%[1]sreflector%[1]srotors%[1]srings%[1]spositions%[1]splugboard", keys.Delimiter)
+	}
+
+	key := keys.Enigma{
+		Model:     parts[0],
+		Reflector: parts[1],
+		Rotors:    strings.Split(parts[2], ","),
+		Rings:     parts[3],
+		Positions: parts[4],
+	}
+	if len(parts) == 6 {
+		key.Plugboard = parts[5]
+	}
+
+	// собираем машину, чтобы проверить роторы, отражатель и коммутационную панель
+	_, err := enigma.New(key, alphabetMap, power)
+	if err != nil {
+		return keys.Enigma{}, err
+	}
+
+	return key, nil
+}
+
+func HomophonicKey(keyString string, alphabetMap map[rune]int) (keys.Homophonic, error) {
+	key := keys.Homophonic{Codes: make(map[rune][]int)}
+	seen := make(map[int]bool)
+
+	for _, entry := range strings.Split(keyString, keys.Delimiter) {
+		entryRunes := []rune(entry)
+		if len(entryRunes) < 3 || entryRunes[1] != ':' {
+			return keys.Homophonic{}, fmt.Errorf("homophonic key entries must look like char:code,code")
+		}
+
+		char := entryRunes[0]
+		if _, ok := alphabetMap[char]; !ok {
+			return keys.Homophonic{}, fmt.Errorf("key contains invalid symbol: '%c'", char)
+		}
+		if _, ok := key.Codes[char]; ok {
+			return keys.Homophonic{}, fmt.Errorf("key contains duplicate symbol: '%c'", char)
+		}
+
+		for _, field := range strings.Split(string(entryRunes[2:]), ",") {
+			code, err := strconv.Atoi(field)
+			if err != nil || code < 0 {
+				return keys.Homophonic{}, fmt.Errorf("homophonic code must be a non-negative number: %q", field)
+			}
+			if seen[code] {
+				return keys.Homophonic{}, fmt.Errorf("code %d is assigned to more than one symbol", code)
+			}
+			seen[code] = true
+
+			key.Codes[char] = append(key.Codes[char], code)
+		}
+	}
+
+	if len(key.Codes) != len(alphabetMap) {
+		return keys.Homophonic{}, fmt.Errorf("homophonic key must contain codes for every symbol of the alphabet")
+	}
+
+	return key, nil
+}
+
+// Substitution key presets, written in key.txt instead of the full permuted alphabet
+const (
+	PresetAtbash  = "atbash"  // atbash
+	PresetROT     = "rot"     // rot|13
+	PresetKeyword = "keyword" // keyword|ЗАМОК or keyword|ЗАМОК|3
+)
+
+// ExpandSubstitutionKey turns a preset into the full substitution alphabet.
+// Any other key is returned unchanged and must be checked with SubstitutionKey.
+func ExpandSubstitutionKey(keyString string, alphabetMap map[rune]int, power int) (string, error) {
+	parts := strings.Split(keyString, keys.Delimiter)
+
+	switch parts[0] {
+	case PresetAtbash:
+		if len(parts) != 1 {
+			return "", fmt.Errorf("atbash preset takes no parameters")
+		}
+		return keygen.Atbash(alphabetMap, power), nil
+	case PresetROT:
+		if len(parts) != 2 {
+			return "", fmt.Errorf("rot preset must look like rot%s13", keys.Delimiter)
+		}
+		n, err := strconv.Atoi(parts[1])
+		if err != nil {
+			return "", fmt.Errorf("rot shift must be a number: %q", parts[1])
+		}
+		return keygen.ROT(n, alphabetMap, power), nil
+	case PresetKeyword:
+		if len(parts) != 2 && len(parts) != 3 {
+			return "", fmt.Errorf("keyword preset must look like keyword%[1]sWORD or keyword%[1]sWORD%[1]sshift", keys.Delimiter)
+		}
+		shift := 0
+		if len(parts) == 3 {
+			var err error
+			shift, err = strconv.Atoi(parts[2])
+			if err != nil {
+				return "", fmt.Errorf("keyword shift must be a number: %q", parts[2])
+			}
+		}
+		return keygen.KeywordAlphabet(parts[1], shift, alphabetMap, power)
+	}
+
+	return keyString, nil
+}
+
+func TrithemiusKey(keyString string, alphabetMap map[rune]int) (keys.Trithemius, error) {
+	parts := strings.Split(keyString, keys.Delimiter)
+	if len(parts) > 2 {
+		return keys.Trithemius{}, fmt.Errorf("trithemius key must look like symbol or symbol%sstep", keys.Delimiter)
+	}
+
+	shift, err := CaesarKey(parts[0], alphabetMap)
+	if err != nil {
+		return keys.Trithemius{}, fmt.Errorf("trithemius key must start with one symbol from the alphabet")
+	}
+
+	step := 1
+	if len(parts) == 2 {
+		step, err = strconv.Atoi(parts[1])
+		if err != nil {
+			return keys.Trithemius{}, fmt.Errorf("trithemius step must be a number: %q", parts[1])
+		}
+	}
+
+	// при нулевом шаге шифр вырождается в шифр Цезаря
+	if step%len(alphabetMap) == 0 {
+		return keys.Trithemius{}, fmt.Errorf("trithemius step must not be a multiple of the power of the alphabet")
+	}
+
+	return keys.Trithemius{Shift: shift, Step: step}, nil
+}
+
+func AlbertiKey(keyString string, alphabetMap map[rune]int, power int) (keys.Alberti, error) {
+	parts := strings.Split(keyString, keys.Delimiter)
+	if len(parts) != 3 {
+		return keys.Alberti{}, fmt.Errorf("alberti key must look like keyword%[1]sindex%[1]speriod", keys.Delimiter)
+	}
+
+	inner, err := keygen.KeywordAlphabet(parts[0], 0, alphabetMap, power)
+	if err != nil {
+		return keys.Alberti{}, err
+	}
+
+	index := []rune(parts[1])
+	if len(index) != 1 {
+		return keys.Alberti{}, fmt.Errorf("alberti index must be one symbol from the alphabet")
+	}
+	if _, ok := alphabetMap[index[0]]; !ok {
+		return keys.Alberti{}, fmt.Errorf("alberti index must be one symbol from the alphabet")
+	}
+
+	period, err := strconv.Atoi(parts[2])
+	if err != nil || period < 1 {
+		return keys.Alberti{}, fmt.Errorf("alberti period must be a positive number: %q", parts[2])
+	}
+
+	key := keys.Alberti{
+		Inner:  []rune(inner),
+		Index:  index[0],
+		Period: period,
+	}
+
+	return key, nil
+}
+
+func PortaKey(keyString string, alphabetMap map[rune]int, power int) error {
+	if power%2 != 0 {
+		return fmt.Errorf("porta cipher is not possible for this alphabet: it splits the alphabet into two halves, so the power must be even (it is %d)", power)
+	}
+
+	if keyString == "" {
+		return fmt.Errorf("porta key can not be empty")
+	}
+
+	return VigenereKey(keyString, alphabetMap, power)
+}
+
+// QuagmireKey checks the key of Quagmire I-IV, variant is the number of the cipher
+func QuagmireKey(keyString string, variant int, alphabetMap map[rune]int, power int) (keys.Quagmire, error) {
+	parts := strings.Split(keyString, keys.Delimiter)
+
+	fields := 2
+	if variant == 4 {
+		fields = 3
+	}
+	if len(parts) != fields {
+		if variant == 4 {
+			return keys.Quagmire{}, fmt.Errorf("quagmire IV key must look like plainKeyword%[1]scipherKeyword%[1]sindicator", keys.Delimiter)
+		}
+		return keys.Quagmire{}, fmt.Errorf("quagmire key must look like keyword%sindicator", keys.Delimiter)
+	}
+
+	indicator := parts[len(parts)-1]
+	if indicator == "" {
+		return keys.Quagmire{}, fmt.Errorf("quagmire indicator can not be empty")
+	}
+	err := VigenereKey(indicator, alphabetMap, power)
+	if err != nil {
+		return keys.Quagmire{}, err
+	}
+
+	straight := keygen.ROT(0, alphabetMap, power)
+	keyed, err := keygen.KeywordAlphabet(parts[0], 0, alphabetMap, power)
+	if err != nil {
+		return keys.Quagmire{}, err
+	}
+
+	key := keys.Quagmire{Indicator: indicator}
+	switch variant {
+	case 1: // ключевой алфавит открытого текста, обычный алфавит шифротекста
+		key.Plain, key.Cipher = []rune(keyed), []rune(straight)
+	case 2: // обычный алфавит открытого текста, ключевой алфавит шифротекста
+		key.Plain, key.Cipher = []rune(straight), []rune(keyed)
+	case 3: // один и тот же ключевой алфавит
+		key.Plain, key.Cipher = []rune(keyed), []rune(keyed)
+	case 4: // два разных ключевых алфавита
+		cipherKeyed, err := keygen.KeywordAlphabet(parts[1], 0, alphabetMap, power)
+		if err != nil {
+			return keys.Quagmire{}, err
+		}
+		key.Plain, key.Cipher = []rune(keyed), []rune(cipherKeyed)
+	default:
+		return keys.Quagmire{}, fmt.Errorf("unknown quagmire variant: %d", variant)
+	}
+
+	return key, nil
+}
+
+func digits(s string) ([]int, error) {
+	var ds []int
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return nil, fmt.Errorf("%q must consist of digits", s)
+		}
+		ds = append(ds, int(r-'0'))
+	}
+	return ds, nil
+}
+
+// VICKey checks the key written as keyword|blanks|seed|transposition. An empty seed turns off
+// the chain addition, so the transposition can be used alone: АСТРОНОМ|379||31452
+func VICKey(keyString string, alphabetMap map[rune]int, power int) (keys.VIC, error) {
+	parts := strings.Split(keyString, keys.Delimiter)
+	if len(parts) < 2 || len(parts) > 4 {
+		return keys.VIC{}, fmt.Errorf("vic key must look like keyword%[1]sblanks%[1]sseed%[1]stransposition, seed and transposition are optional", keys.Delimiter)
+	}
+
+	blanks, err := digits(parts[1])
+	if err != nil {
+		return keys.VIC{}, err
+	}
+
+	board, err := keygen.Checkerboard(parts[0], blanks, alphabetMap, power)
+	if err != nil {
+		return keys.VIC{}, err
+	}
+
+	key := keys.VIC{Board: board}
+	// пустой третий раздел означает шифр без сложения с гаммой, но с перестановкой
+	if len(parts) > 2 && parts[2] != "" {
+		if len(parts[2]) < 2 {
+			return keys.VIC{}, fmt.Errorf("chain addition seed must contain at least 2 digits")
+		}
+		if _, err := digits(parts[2]); err != nil {
+			return keys.VIC{}, err
+		}
+		key.Seed = parts[2]
+	}
+	if len(parts) > 3 {
+		if len(parts[3]) < 2 {
+			return keys.VIC{}, fmt.Errorf("transposition key must contain at least 2 digits")
+		}
+		if _, err := digits(parts[3]); err != nil {
+			return keys.VIC{}, err
+		}
+		key.Transposition = parts[3]
+	}
+
+	return key, nil
+}
+
+// PadKey parses the one-time pad key. Encryption and decryption require the offset of the segment,
+// it is added to a bare path when the key is generated
+func PadKey(keyString string, needOffset bool) (keys.Pad, error) {
+	parts := strings.Split(keyString, keys.Delimiter)
+	if len(parts) > 2 || parts[0] == "" {
+		return keys.Pad{}, fmt.Errorf("one-time pad key must look like path or path%soffset", keys.Delimiter)
+	}
+
+	key := keys.Pad{Path: parts[0], Offset: -1}
+	if len(parts) == 2 {
+		offset, err := strconv.Atoi(parts[1])
+		if err != nil || offset < 0 {
+			return keys.Pad{}, fmt.Errorf("pad offset must be a non-negative number: %q", parts[1])
+		}
+		key.Offset = offset
+	}
+
+	if needOffset && key.Offset < 0 {
+		return keys.Pad{}, fmt.Errorf("pad key needs the offset of the segment: path%soffset", keys.Delimiter)
+	}
+
+	return key, nil
+}
+
+// Ciphertext encodings of byte-oriented ciphers
+const (
+	EncodingHex    = "hex"
+	EncodingBase64 = "base64"
+)
+
+// BlockMode checks the name of the mode of operation
+func BlockMode(mode string) error {
+	for _, m := range encrypt.Modes {
+		if m == mode {
+			return nil
+		}
+	}
+	return fmt.Errorf("mode of operation must be one of %s", strings.Join(encrypt.Modes, ", "))
+}
+
+func BlockKey(keyString string, size int) (keys.Block, error) {
+	parts := strings.Split(keyString, keys.Delimiter)
+	if len(parts) > 3 {
+		return keys.Block{}, fmt.Errorf("block cipher key must look like hexkey%[1]smode%[1]sencoding, mode and encoding are optional", keys.Delimiter)
+	}
+
+	key, err := hex.DecodeString(parts[0])
+	if err != nil {
+		return keys.Block{}, fmt.Errorf("block cipher key must be written in hex: %v", err)
+	}
+	if len(key) != size {
+		return keys.Block{}, fmt.Errorf("block cipher key must be %d bytes (%d hex digits) long", size, 2*size)
+	}
+
+	block := keys.Block{Key: key, Mode: encrypt.ModeECB, Encoding: EncodingHex}
+	for _, part := range parts[1:] {
+		if part == EncodingHex || part == EncodingBase64 {
+			block.Encoding = part
+			continue
+		}
+
+		err := BlockMode(part)
+		if err != nil {
+			return keys.Block{}, fmt.Errorf("%q is neither an encoding (%s, %s) nor a mode: %v", part, EncodingHex, EncodingBase64, err)
+		}
+		block.Mode = part
+	}
+
+	return block, nil
+}
+
+// HillModeKey checks the Hill key followed by the mode of operation, e.g. ГДЕИ|cbc.
+// The mode is empty when the key has none
+func HillModeKey(keyString string, alphabetMap map[rune]int, power int) ([2][2]int, string, error) {
+	matrix, mode, _ := strings.Cut(keyString, keys.Delimiter)
+
+	key, err := HillKey(matrix, alphabetMap, power)
+	if err != nil {
+		return [2][2]int{}, "", err
+	}
+
+	if mode != "" {
+		err = BlockMode(mode)
+		if err != nil {
+			return [2][2]int{}, "", err
+		}
+	}
+
+	return key, mode, nil
+}
+
+// LFSRKey checks the register key written as kind|taps|seed, e.g. galois|16,14,13,11|1010110011100001
+func LFSRKey(keyString string) (keys.LFSR, error) {
+	parts := strings.Split(keyString, keys.Delimiter)
+	if len(parts) != 3 {
+		return keys.LFSR{}, fmt.Errorf("lfsr key must look like kind%[1]staps%[1]sseed, e.g. %s%[1]s16,14,13,11%[1]s1010110011100001", keys.Delimiter, stream.Fibonacci)
+	}
+
+	key := keys.LFSR{Kind: parts[0]}
+	for _, tap := range strings.Split(parts[1], ",") {
+		t, err := strconv.Atoi(strings.TrimSpace(tap))
+		if err != nil {
+			return keys.LFSR{}, fmt.Errorf("lfsr taps must be numbers separated by commas: %v", err)
+		}
+		key.Taps = append(key.Taps, t)
+	}
+
+	seed, err := strconv.ParseUint(parts[2], 2, 64)
+	if err != nil {
+		return keys.LFSR{}, fmt.Errorf("lfsr seed must be written in binary: %v", err)
+	}
+	key.Seed = seed
+
+	g, err := stream.NewLFSR(key.Kind, key.Taps, key.Seed)
+	if err != nil {
+		return keys.LFSR{}, err
+	}
+
+	// при периоде, делящем 8, гамма - один и тот же байт, например 1|1 дает только единицы
+	if period, ok := stream.Period(g, 8); ok && 8%period == 0 {
+		return keys.LFSR{}, fmt.Errorf("lfsr with this seed has period %d, its keystream is one byte repeated", period)
+	}
+
+	return key, nil
+}
+
+// A51Key checks the A5/1 key written as the 64-bit key in hex and the frame number, e.g. 1223456789abcdef|0x134
+func A51Key(keyString string) (keys.A51, error) {
+	key, frame, found := strings.Cut(keyString, keys.Delimiter)
+	if !found {
+		return keys.A51{}, fmt.Errorf("a5/1 key must look like hexkey%sframe", keys.Delimiter)
+	}
+
+	if len(key) != 16 {
+		return keys.A51{}, fmt.Errorf("a5/1 key must be 16 hex digits long")
+	}
+	k, err := strconv.ParseUint(key, 16, 64)
+	if err != nil {
+		return keys.A51{}, fmt.Errorf("a5/1 key must be written in hex: %v", err)
+	}
+
+	// номер кадра можно записать в десятичном виде или с префиксом 0x
+	f, err := strconv.ParseUint(frame, 0, 22)
+	if err != nil {
+		return keys.A51{}, fmt.Errorf("a5/1 frame number must be a 22-bit number: %v", err)
+	}
+
+	return keys.A51{Key: k, Frame: uint32(f)}, nil
+}
+
+// decimals splits the key into decimal numbers, there must be one of the allowed counts
+func decimals(keyString, name, format string, counts ...int) ([]*big.Int, error) {
+	parts := strings.Split(keyString, keys.Delimiter)
+
+	allowed := false
+	for _, count := range counts {
+		allowed = allowed || len(parts) == count
+	}
+	if !allowed {
+		return nil, fmt.Errorf("%s key must look like %s, or new%sbits to generate one", name, format, keys.Delimiter)
+	}
+
+	numbers := make([]*big.Int, len(parts))
+	for i, part := range parts {
+		n, ok := new(big.Int).SetString(part, 10)
+		if !ok {
+			return nil, fmt.Errorf("%s key parts must be decimal numbers: %q", name, part)
+		}
+		numbers[i] = n
+	}
+	return numbers, nil
+}
+
+// RSAKey reads the RSA key written in decimal as n|e|d, or n|e when only the public key is known.
+// D is nil for a public key
+func RSAKey(keyString string) (*rsa.PrivateKey, error) {
+	numbers, err := decimals(keyString, "rsa", fmt.Sprintf("n%[1]se%[1]sd or n%[1]se", keys.Delimiter), 2, 3)
+	if err != nil {
+		return nil, err
+	}
+
+	key := &rsa.PrivateKey{PublicKey: rsa.PublicKey{N: numbers[0], E: numbers[1]}}
+	if len(numbers) == 3 {
+		key.D = numbers[2]
+	}
+
+	err = key.Validate()
+	if err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// GroupKey reads the prime and the generator written in decimal as p|g
+func GroupKey(keyString string) (*asymmetric.Group, error) {
+	numbers, err := decimals(keyString, "group", fmt.Sprintf("p%sg", keys.Delimiter), 2)
+	if err != nil {
+		return nil, err
+	}
+
+	group := &asymmetric.Group{P: numbers[0], G: numbers[1]}
+	err = group.Validate()
+	if err != nil {
+		return nil, err
+	}
+	return group, nil
+}
+
+// ElGamalKey reads the ElGamal key written in decimal as p|g|y|x, or p|g|y when only the public key is known.
+// X is nil for a public key
+func ElGamalKey(keyString string) (*elgamal.PrivateKey, error) {
+	numbers, err := decimals(keyString, "elgamal", fmt.Sprintf("p%[1]sg%[1]sy%[1]sx or p%[1]sg%[1]sy", keys.Delimiter), 3, 4)
+	if err != nil {
+		return nil, err
+	}
+
+	key := &elgamal.PrivateKey{PublicKey: elgamal.PublicKey{Group: asymmetric.Group{P: numbers[0], G: numbers[1]}, Y: numbers[2]}}
+	if len(numbers) == 4 {
+		key.X = numbers[3]
+	}
+
+	err = key.Validate()
+	if err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// ShamirKey reads the prime and the exponents of Alice and Bob written in decimal as p|cA|cB
+func ShamirKey(keyString string) (*shamir.Protocol, error) {
+	numbers, err := decimals(keyString, "shamir", fmt.Sprintf("p%[1]scA%[1]scB", keys.Delimiter), 3)
+	if err != nil {
+		return nil, err
+	}
+	return shamir.New(numbers[0], numbers[1], numbers[2])
+}
+
+// ECCKey reads the GOST R 34.10-2012 key written as curve|d, or curve|x|y when only the public point is known.
+// Numbers are decimal or hex with the 0x prefix, D is nil for a public key
+func ECCKey(keyString string) (*ecc.PrivateKey, error) {
+	parts := strings.Split(keyString, keys.Delimiter)
+	if len(parts) != 2 && len(parts) != 3 {
+		return nil, fmt.Errorf("gost 34.10 key must look like curve%[1]sd or curve%[1]sx%[1]sy, or new%[1]scurve to generate one", keys.Delimiter)
+	}
+
+	c, err := ecc.Lookup(parts[0])
+	if err != nil {
+		return nil, err
+	}
+
+	if len(parts) == 2 {
+		d, ok := new(big.Int).SetString(parts[1], 0)
+		if !ok {
+			return nil, fmt.Errorf("gost 34.10 secret key must be a number: %q", parts[1])
+		}
+		return ecc.NewPrivateKey(c, d)
+	}
+
+	public, err := c.ParsePoint(parts[1] + "," + parts[2])
+	if err != nil {
+		return nil, err
+	}
+	if public.IsInfinity() {
+		return nil, fmt.Errorf("gost 34.10 public key can not be the point at infinity")
+	}
+	return &ecc.PrivateKey{Curve: c, Public: public}, nil
+}
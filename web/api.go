@@ -0,0 +1,173 @@
+package web
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"unicode/utf8"
+
+	"github.com/marelinaa/cipher-algorithms/registry"
+	"github.com/marelinaa/cipher-algorithms/verify"
+)
+
+// Value is the part of syscall/js.Value the API reads its arguments from,
+// outside the browser the API is called with stand-ins implementing it
+type Value interface {
+	IsString() bool
+	String() string
+}
+
+// Func is a function of the JavaScript API. The result is built from maps, slices, strings and bools,
+// so js.ValueOf converts it to a JavaScript object
+type Func func(args []Value) any
+
+// Funcs are the functions set on the global cipher object:
+//
+//	cipher.ciphers()
+//	cipher.encrypt(name, alphabet, key, text)
+//	cipher.decrypt(name, alphabet, key, text)
+//	cipher.keygen(name, alphabet, params)
+//
+// encrypt, decrypt and keygen return {result} or {error}
+var Funcs = map[string]Func{
+	"ciphers": safe(ciphers),
+	"encrypt": safe(func(args []Value) any { return crypt(args, true) }),
+	"decrypt": safe(func(args []Value) any { return crypt(args, false) }),
+	"keygen":  safe(keygen),
+}
+
+// safe turns a panic of a cipher into {error}: an unrecovered panic stops the WebAssembly runtime
+// and the API stops working for the whole page
+func safe(f Func) Func {
+	return func(args []Value) (result any) {
+		defer func() {
+			if r := recover(); r != nil {
+				result = failure(fmt.Errorf("cipher failed: %v", r))
+			}
+		}()
+		return f(args)
+	}
+}
+
+// Names returns the names of the API functions in alphabetical order
+func Names() []string {
+	names := make([]string, 0, len(Funcs))
+	for name := range Funcs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func ciphers(args []Value) any {
+	var list []any
+	for _, c := range registry.All() {
+		// в браузере нет файловой системы для ключей-блокнотов
+		if c.Files {
+			continue
+		}
+		list = append(list, map[string]any{
+			"id":         c.ID,
+			"name":       c.Name,
+			"keyFormat":  c.KeyFormat,
+			"plaintext":  c.Plaintext.String(),
+			"ciphertext": c.Ciphertext.String(),
+			"encrypt":    c.Encrypt != nil,
+			"keygen":     c.NewKey != nil,
+		})
+	}
+	return list
+}
+
+func crypt(args []Value, encryption bool) any {
+	strs, err := stringArgs(args, "name", "alphabet", "key", "text")
+	if err != nil {
+		return failure(err)
+	}
+	c, alphabetMap, power, err := prepare(strs[0], strs[1])
+	if err != nil {
+		return failure(err)
+	}
+
+	f, format := c.Encrypt, c.Plaintext
+	if !encryption {
+		f, format = c.Decrypt, c.Ciphertext
+	}
+	if f == nil {
+		return failure(fmt.Errorf("%s can only sign, it does not encrypt", c.ID))
+	}
+	if strs[2] == "" {
+		return failure(fmt.Errorf("key can not be empty"))
+	}
+	if format == registry.Alphabet {
+		if err := verify.Text(strs[3], alphabetMap); err != nil {
+			return failure(err)
+		}
+	}
+
+	result, err := f(strs[3], strs[2], alphabetMap, power)
+	if err != nil {
+		return failure(err)
+	}
+	return map[string]any{"result": result}
+}
+
+func keygen(args []Value) any {
+	strs, err := stringArgs(args, "name", "alphabet", "params")
+	if err != nil {
+		return failure(err)
+	}
+	c, alphabetMap, power, err := prepare(strs[0], strs[1])
+	if err != nil {
+		return failure(err)
+	}
+	if c.NewKey == nil {
+		return failure(fmt.Errorf("%s has no key generation", c.ID))
+	}
+
+	key, generated, err := c.NewKey(context.Background(), strs[2], alphabetMap, power)
+	if err != nil {
+		return failure(err)
+	}
+	if !generated {
+		return failure(fmt.Errorf("params do not ask for a new key, the key format is: %s", c.KeyFormat))
+	}
+	return map[string]any{"result": key}
+}
+
+// stringArgs checks that the arguments are the named strings
+func stringArgs(args []Value, names ...string) ([]string, error) {
+	if len(args) != len(names) {
+		return nil, fmt.Errorf("expected %d arguments, got %d", len(names), len(args))
+	}
+
+	strs := make([]string, len(args))
+	for i, arg := range args {
+		if !arg.IsString() {
+			return nil, fmt.Errorf("%s must be a string", names[i])
+		}
+		strs[i] = arg.String()
+	}
+	return strs, nil
+}
+
+// prepare finds the cipher and checks the alphabet
+func prepare(name, alphabet string) (registry.Cipher, map[rune]int, int, error) {
+	c, ok := registry.Lookup(name)
+	if !ok || c.Files {
+		return registry.Cipher{}, nil, 0, fmt.Errorf("unknown cipher %q", name)
+	}
+	if alphabet == "" {
+		return registry.Cipher{}, nil, 0, fmt.Errorf("alphabet can not be empty")
+	}
+
+	alphabetMap, err := verify.Alphabet(alphabet)
+	if err != nil {
+		return registry.Cipher{}, nil, 0, err
+	}
+	return c, alphabetMap, utf8.RuneCountInString(alphabet), nil
+}
+
+func failure(err error) any {
+	return map[string]any{"error": err.Error()}
+}
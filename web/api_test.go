@@ -0,0 +1,117 @@
+package web
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/marelinaa/cipher-algorithms/registry"
+)
+
+// str and number stand in for js.Value holding a string and a number
+type str string
+
+func (s str) IsString() bool { return true }
+func (s str) String() string { return string(s) }
+
+type number int
+
+func (n number) IsString() bool { return false }
+func (n number) String() string { return "<number>" }
+
+const alphabet = "АБВГДЕЁЖЗИЙКЛМНОПРСТУФХЦЧШЩЪЫЬЭЮЯ "
+
+func init() {
+	// шифр, который падает с паникой, как падал шифр с непроверенным ключом
+	registry.Register(registry.Cipher{
+		ID:   "panic",
+		Name: "Cipher that panics",
+		Encrypt: func(input, key string, alphabetMap map[rune]int, power int) (string, error) {
+			panic("index out of range")
+		},
+		Decrypt: func(input, key string, alphabetMap map[rune]int, power int) (string, error) {
+			return input, nil
+		},
+		NewKey: func(ctx context.Context, params string, alphabetMap map[rune]int, power int) (string, bool, error) {
+			panic("index out of range")
+		},
+	})
+}
+
+func call(t *testing.T, name string, args ...Value) map[string]any {
+	t.Helper()
+	result, ok := Funcs[name](args).(map[string]any)
+	if !ok {
+		t.Fatalf("%s returned %T, want a map", name, Funcs[name](args))
+	}
+	return result
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, tt := range []struct{ cipher, key string }{
+		{"caesar", "В"},
+		{"vigenere", "КЛЮЧ"},
+		{"magma", strings.Repeat("ab", 32) + "|ctr"},
+	} {
+		text := "ПРИВЕТ МИР"
+		encrypted := call(t, "encrypt", str(tt.cipher), str(alphabet), str(tt.key), str(text))
+		if encrypted["error"] != nil {
+			t.Fatalf("%s: encrypt: %v", tt.cipher, encrypted["error"])
+		}
+		decrypted := call(t, "decrypt", str(tt.cipher), str(alphabet), str(tt.key), str(encrypted["result"].(string)))
+		// несимвольные шифры возвращают байты, для русского текста это тот же UTF-8
+		if decrypted["result"] != text {
+			t.Errorf("%s: decrypt = %v, want %q", tt.cipher, decrypted, text)
+		}
+	}
+}
+
+func TestErrors(t *testing.T) {
+	tests := []struct {
+		name, fn string
+		args     []Value
+		want     string
+	}{
+		{"missing argument", "encrypt", []Value{str("caesar"), str(alphabet), str("В")}, "expected 4 arguments"},
+		{"number argument", "encrypt", []Value{str("caesar"), str(alphabet), number(3), str("А")}, "key must be a string"},
+		{"unknown cipher", "encrypt", []Value{str("nope"), str(alphabet), str("В"), str("А")}, "unknown cipher"},
+		{"pad cipher", "encrypt", []Value{str("otp"), str(alphabet), str("pad.txt"), str("А")}, "unknown cipher"},
+		{"symbol outside the alphabet", "encrypt", []Value{str("caesar"), str(alphabet), str("В"), str("HELLO")}, ""},
+		{"small checkerboard", "encrypt", []Value{str("vic"), str("ABCDE"), str("AB|1"), str("ABC")}, "top row"},
+		{"panic in encrypt", "encrypt", []Value{str("panic"), str(alphabet), str("В"), str("А")}, "cipher failed"},
+		{"panic in keygen", "keygen", []Value{str("panic"), str(alphabet), str("new")}, "cipher failed"},
+	}
+	for _, tt := range tests {
+		result := call(t, tt.fn, tt.args...)
+		err, ok := result["error"].(string)
+		if !ok || !strings.Contains(err, tt.want) {
+			t.Errorf("%s: got %v, want an error containing %q", tt.name, result, tt.want)
+		}
+	}
+}
+
+func TestKeygen(t *testing.T) {
+	result := call(t, "keygen", str("rsa"), str(alphabet), str("new|64"))
+	key, ok := result["result"].(string)
+	if !ok || strings.Count(key, "|") != 2 {
+		t.Fatalf("keygen = %v, want n|e|d", result)
+	}
+
+	encrypted := call(t, "encrypt", str("rsa"), str(alphabet), str(key), str("ПРИВЕТ"))
+	decrypted := call(t, "decrypt", str("rsa"), str(alphabet), str(key), str(encrypted["result"].(string)))
+	if decrypted["result"] != "ПРИВЕТ" {
+		t.Errorf("rsa with the generated key: %v, %v", encrypted, decrypted)
+	}
+}
+
+func TestCiphers(t *testing.T) {
+	list, ok := Funcs["ciphers"](nil).([]any)
+	if !ok || len(list) == 0 {
+		t.Fatalf("ciphers returned %v", Funcs["ciphers"](nil))
+	}
+	for _, item := range list {
+		if item.(map[string]any)["id"] == "otp" {
+			t.Error("ciphers lists otp, its key names a file")
+		}
+	}
+}
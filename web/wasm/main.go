@@ -0,0 +1,40 @@
+//go:build js && wasm
+
+// Command wasm exposes the ciphers to JavaScript as the global cipher object, see web.Funcs.
+// Build it with
+//
+//	GOOS=js GOARCH=wasm go build -o cipher.wasm ./web/wasm
+package main
+
+import (
+	"syscall/js"
+
+	"github.com/marelinaa/cipher-algorithms/web"
+)
+
+// value adapts js.Value to web.Value
+type value struct {
+	js.Value
+}
+
+func (v value) IsString() bool {
+	return v.Type() == js.TypeString
+}
+
+func main() {
+	cipher := js.Global().Get("Object").New()
+	for _, name := range web.Names() {
+		f := web.Funcs[name]
+		cipher.Set(name, js.FuncOf(func(this js.Value, args []js.Value) any {
+			values := make([]web.Value, len(args))
+			for i, arg := range args {
+				values[i] = value{arg}
+			}
+			return js.ValueOf(f(values))
+		}))
+	}
+	js.Global().Set("cipher", cipher)
+
+	// функции вызываются из JavaScript, пока страница открыта
+	select {}
+}